@@ -0,0 +1,320 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Status values for the results of the all command
+const (
+	StatusPass    = "PASS"
+	StatusFail    = "FAIL"
+	StatusError   = "ERROR"
+	StatusUnknown = "-"
+)
+
+// RunResult is the outcome of running a single day/puzzle/input combination
+type RunResult struct {
+	DayNum    int
+	PuzzleNum int
+	IsSample  bool
+	Answer    string
+	Expected  string
+	Elapsed   time.Duration
+	Status    string
+	Err       error
+	Record    *cmn.RunRecord
+}
+
+// Failed reports if the run errored or didn't match the known answer
+func (r *RunResult) Failed() bool {
+	return r.Status == StatusFail || r.Status == StatusError
+}
+
+// Cause returns the error behind a failed run, nil if it didn't fail
+func (r *RunResult) Cause() error {
+	switch r.Status {
+	case StatusError:
+		return r.Err
+	case StatusFail:
+		return &cmn.AnswerMismatchError{
+			DayNum:    r.DayNum,
+			PuzzleNum: r.PuzzleNum,
+			IsSample:  r.IsSample,
+			Expected:  r.Expected,
+			Answer:    r.Answer,
+		}
+	}
+	return nil
+}
+
+// allCmd runs every registered day and puzzle and summarizes the results
+var allCmd = &cobra.Command{
+	Use:          "all",
+	Short:        "Run every implemented day and puzzle",
+	Long:         `Runs every registered day and puzzle against the sample and/or puzzle data and prints a summary table`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Each day reads its own data, a single input file can't apply to all of them
+		if cmd.Flags().Changed("input") {
+			return errors.New("--input can't be used with all, each day runs against its own data")
+		}
+
+		inputs, err := cmd.Flags().GetString("inputs")
+		if err != nil {
+			return err
+		}
+		samples, err := inputSelection(inputs)
+		if err != nil {
+			return err
+		}
+
+		parallel, err := cmd.Flags().GetBool("parallel")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		results := RunAll(cmd.Context(), cmn.RegisteredDays(), samples, parallel)
+		if format == cmn.FormatText {
+			PrintResults(os.Stdout, results)
+		} else if err = PrintRecords(os.Stdout, format, results); err != nil {
+			return err
+		}
+
+		// The first failure's cause determines the exit code
+		failed := 0
+		var cause error
+		for _, result := range results {
+			if !result.Failed() {
+				continue
+			}
+			failed++
+			if cause == nil {
+				cause = result.Cause()
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d runs failed, first failure: %w", failed, len(results), cause)
+		}
+		return nil
+	},
+}
+
+func init() {
+	allCmd.Flags().StringP("inputs", "i", "both", "Which inputs to run: sample, puzzle or both")
+	allCmd.Flags().BoolP("parallel", "P", false, "Run the days concurrently")
+}
+
+// inputSelection converts the inputs flag value into the IsSample values to run
+func inputSelection(inputs string) ([]bool, error) {
+	switch inputs {
+	case "sample":
+		return []bool{true}, nil
+	case "puzzle":
+		return []bool{false}, nil
+	case "both":
+		return []bool{true, false}, nil
+	}
+	return nil, fmt.Errorf("invalid inputs value %q, expected sample, puzzle or both", inputs)
+}
+
+// RunAll runs every puzzle of the days (e.g. the registered days) for each of
+// the sample values. If parallel is set, each day is run in its own goroutine,
+// the puzzles of a single day are run sequentially. Solvers keep their state
+// to themselves, so any of them may run concurrently. Runs still pending once
+// the context is done fail with its error
+func RunAll(ctx context.Context, days []*cmn.DailyPuzzle, samples []bool, parallel bool) []*RunResult {
+	dayResults := make([][]*RunResult, len(days))
+
+	var wg sync.WaitGroup
+	for i, day := range days {
+		run := func() {
+			dayResults[i] = runDay(ctx, day, samples)
+		}
+		if parallel {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				run()
+			}()
+		} else {
+			run()
+		}
+	}
+	wg.Wait()
+
+	results := []*RunResult{}
+	for _, dayResult := range dayResults {
+		for _, result := range dayResult {
+			// Allocations are measured process wide, so they overlap in parallel
+			result.Record.Concurrent = parallel
+		}
+		results = append(results, dayResult...)
+	}
+	return results
+}
+
+// runDay runs each of a day's puzzles for each of the sample values
+func runDay(ctx context.Context, day *cmn.DailyPuzzle, samples []bool) []*RunResult {
+	results := []*RunResult{}
+
+	for puzzleNum := 1; puzzleNum <= len(day.Solvers); puzzleNum++ {
+		for _, isSample := range samples {
+			result := runPuzzle(ctx, day, puzzleNum, isSample)
+			result.Status = resultStatus(result)
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].PuzzleNum < results[j].PuzzleNum
+	})
+	return results
+}
+
+// runPuzzle runs a single puzzle, timing the solver and checking the answer
+// against the day's answers file. The solver's own output, profiles and progress
+// are discarded, as they would clutter the table
+func runPuzzle(ctx context.Context, day *cmn.DailyPuzzle, puzzle int, isSample bool) *RunResult {
+	result := &RunResult{DayNum: day.DayNum, PuzzleNum: puzzle, IsSample: isSample}
+
+	handler, err := cmn.NewHandlerE(
+		nil,
+		cmn.WithPuzzle(day.DayNum, puzzle, isSample),
+		cmn.WithSolvers(day.Solvers...),
+		cmn.WithContext(ctx),
+		cmn.WithOutput(io.Discard),
+		cmn.WithProfileOutput(io.Discard),
+		cmn.WithProgressOutput(io.Discard),
+	)
+	defer handler.Close()
+	if err != nil {
+		result.Err = err
+		result.Expected = handler.Expected
+		result.Record = handler.Record(err)
+		return result
+	}
+
+	start := time.Now()
+	result.Err = handler.Solve()
+	result.Elapsed = time.Since(start)
+	result.Answer = handler.Answer
+	result.Expected = handler.Expected
+	result.Record = handler.Record(result.Err)
+
+	// Wrong answers are reported with the FAIL status rather than as errors
+	var mismatch *cmn.AnswerMismatchError
+	if errors.As(result.Err, &mismatch) {
+		result.Err = nil
+	}
+
+	return result
+}
+
+// resultStatus determines the status of the result by comparing it against the
+// expected answer
+func resultStatus(result *RunResult) string {
+	switch {
+	case result.Err != nil:
+		return StatusError
+	case result.Expected == "":
+		return StatusUnknown
+	case result.Answer == result.Expected:
+		return StatusPass
+	}
+	return StatusFail
+}
+
+// PrintResults prints the results as a table
+func PrintResults(out io.Writer, results []*RunResult) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DAY\tPUZZLE\tINPUT\tANSWER\tEXPECTED\tELAPSED\tSTATUS")
+
+	for _, result := range results {
+		input := "puzzle"
+		if result.IsSample {
+			input = "sample"
+		}
+		status := result.Status
+		if result.Err != nil {
+			status += ": " + strings.TrimSpace(result.Err.Error())
+		}
+		fmt.Fprintf(
+			writer,
+			"%d\t%d\t%s\t%s\t%s\t%.5fs\t%s\n",
+			result.DayNum,
+			result.PuzzleNum,
+			input,
+			orDash(result.Answer),
+			orDash(result.Expected),
+			result.Elapsed.Seconds(),
+			status,
+		)
+	}
+	writer.Flush()
+}
+
+// PrintRecords writes a record of each result in the json or csv format. The
+// record's error is the cause of a failed run, so wrong answers are included
+func PrintRecords(out io.Writer, format string, results []*RunResult) error {
+	writer, err := cmn.NewRecordWriter(out, format)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		record := *result.Record
+		if cause := result.Cause(); cause != nil {
+			record.Error = strings.TrimSpace(cause.Error())
+		}
+		if err = writer.Write(&record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orDash replaces empty values with a dash for the table output
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// countLines reports the number of lines in the input, plus the offset
+func countLines(offset int) cmn.HandlerFunc {
+	return func(h *cmn.AdventHandler) error {
+		defer h.StartProfile("countLines")()
+		lines := offset
+		for h.Scan() {
+			lines++
+		}
+		h.Report("Lines:", lines)
+		return nil
+	}
+}
+
+// stubDays writes the data for two stub days to a temporary data directory
+// and returns them, in place of the registered days
+func stubDays(t *testing.T) []*cmn.DailyPuzzle {
+	t.Helper()
+	dataDir := cmn.DataDir
+	cmn.DataDir = t.TempDir()
+	t.Cleanup(func() { cmn.DataDir = dataDir })
+
+	files := map[int]map[string]string{
+		1: {
+			cmn.SampleFileName: "a\nb\n",
+			cmn.InputFileName:  "a\nb\nc\n",
+			cmn.AnswersFile:    "sample1: 2\npuzzle1: 3\nsample2: 2\n",
+		},
+		// Day 2 has no sample data
+		2: {cmn.InputFileName: "a\n"},
+	}
+	for day, dayFiles := range files {
+		os.MkdirAll(cmn.DayDir(day), 0o755)
+		for name, content := range dayFiles {
+			if err := os.WriteFile(filepath.Join(cmn.DayDir(day), name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return []*cmn.DailyPuzzle{
+		// Part two is off by one, so its sample fails
+		{DayNum: 1, Solvers: []cmn.HandlerFunc{countLines(0), countLines(1)}},
+		{DayNum: 2, Solvers: []cmn.HandlerFunc{func(h *cmn.AdventHandler) error {
+			return errors.New("broken")
+		}}},
+	}
+}
+
+func TestRunAll(t *testing.T) {
+	days := stubDays(t)
+	// The solvers' profiles are discarded without touching the global writer
+	var profiles bytes.Buffer
+	profileOut := cmn.ProfileOut
+	cmn.ProfileOut = &profiles
+	defer func() { cmn.ProfileOut = profileOut }()
+
+	expected := []struct {
+		day, puzzle int
+		isSample    bool
+		answer      string
+		status      string
+	}{
+		{1, 1, true, "2", StatusPass},
+		{1, 1, false, "3", StatusPass},
+		{1, 2, true, "3", StatusFail},
+		{1, 2, false, "4", StatusUnknown},
+		{2, 1, true, "", StatusError},
+		{2, 1, false, "", StatusError},
+	}
+
+	for _, parallel := range []bool{false, true} {
+		t.Run("parallel="+strconv.FormatBool(parallel), func(t *testing.T) {
+			results := RunAll(context.Background(), days, []bool{true, false}, parallel)
+			if len(results) != len(expected) {
+				t.Fatalf("got %d results, want %d", len(results), len(expected))
+			}
+			for i, want := range expected {
+				result := results[i]
+				if result.DayNum != want.day || result.PuzzleNum != want.puzzle || result.IsSample != want.isSample ||
+					result.Answer != want.answer || result.Status != want.status {
+					t.Errorf("result %d = %+v, want %+v", i, *result, want)
+				}
+				if result.Record == nil || result.Record.Concurrent != parallel {
+					t.Errorf("result %d record = %+v", i, result.Record)
+				}
+			}
+
+			var mismatch *cmn.AnswerMismatchError
+			if !errors.As(results[2].Cause(), &mismatch) || mismatch.Expected != "2" {
+				t.Errorf("expected the failed sample to be caused by a mismatch, got %v", results[2].Cause())
+			}
+			var notFound *cmn.InputNotFoundError
+			if !errors.As(results[4].Err, &notFound) {
+				t.Errorf("expected the missing sample to be reported, got %v", results[4].Err)
+			}
+			if results[5].Err == nil || results[5].Err.Error() != "broken" {
+				t.Errorf("expected the solver error, got %v", results[5].Err)
+			}
+			if profiles.Len() != 0 {
+				t.Errorf("expected the profiles to be discarded, got:\n%s", profiles.String())
+			}
+		})
+	}
+}
+
+func TestRunAllCancelled(t *testing.T) {
+	days := stubDays(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, result := range RunAll(ctx, days, []bool{false}, true) {
+		if result.Status != StatusError || !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("expected day %d puzzle %d to be cancelled, got %v", result.DayNum, result.PuzzleNum, result.Err)
+		}
+	}
+}
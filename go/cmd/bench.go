@@ -0,0 +1,210 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// benchCmd runs the solver benchmarks and compares them against a saved baseline
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Run the solver benchmarks and report regressions",
+	Long: `Runs the solver benchmarks repeatedly with go test, storing the raw output
+in the --out file. If a baseline exists the median ns/op of each benchmark is
+compared against it, and the command fails if any benchmark got significantly
+slower (Mann-Whitney U test p-value below --alpha) by more than --threshold.
+Use --save-baseline to store the results as the new baseline instead.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := readBenchFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		output, err := runBenchmarks(opts.srcDir, opts.pattern, opts.packages, opts.count)
+		if err != nil {
+			return err
+		}
+
+		outPath := opts.outPath
+		if opts.saveBaseline {
+			outPath = opts.baselinePath
+		}
+		if err := cmn.WriteFileAtomic(resolveSrcPath(opts.srcDir, outPath), output); err != nil {
+			return err
+		}
+		fmt.Println("Wrote", outPath)
+		if opts.saveBaseline {
+			return nil
+		}
+
+		baselineFile, err := os.Open(resolveSrcPath(opts.srcDir, opts.baselinePath))
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Println("No baseline found, run with --save-baseline to create one")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		defer baselineFile.Close()
+
+		baseline, err := cmn.ParseBenchOutput(baselineFile)
+		if err != nil {
+			return err
+		}
+		current, err := cmn.ParseBenchOutput(bytes.NewReader(output))
+		if err != nil {
+			return err
+		}
+
+		comparisons := cmn.CompareBench(baseline, current, opts.alpha, opts.threshold)
+		regressions := PrintBenchComparisons(os.Stdout, comparisons)
+		if regressions > 0 {
+			return fmt.Errorf("%d benchmark(s) regressed against %s", regressions, opts.baselinePath)
+		}
+		return nil
+	},
+}
+
+// benchOptions holds the bench command's flags
+type benchOptions struct {
+	srcDir       string
+	pattern      string
+	packages     string
+	count        int
+	outPath      string
+	baselinePath string
+	saveBaseline bool
+	alpha        float64
+	threshold    float64
+}
+
+// readBenchFlags reads the bench command's flags
+func readBenchFlags(cmd *cobra.Command) (opts benchOptions, err error) {
+	flags := cmd.Flags()
+	if opts.srcDir, err = flags.GetString("src"); err != nil {
+		return opts, err
+	}
+	if opts.pattern, err = flags.GetString("bench"); err != nil {
+		return opts, err
+	}
+	if opts.packages, err = flags.GetString("packages"); err != nil {
+		return opts, err
+	}
+	if opts.count, err = flags.GetInt("count"); err != nil {
+		return opts, err
+	}
+	if opts.outPath, err = flags.GetString("out"); err != nil {
+		return opts, err
+	}
+	if opts.baselinePath, err = flags.GetString("baseline"); err != nil {
+		return opts, err
+	}
+	if opts.saveBaseline, err = flags.GetBool("save-baseline"); err != nil {
+		return opts, err
+	}
+	if opts.alpha, err = flags.GetFloat64("alpha"); err != nil {
+		return opts, err
+	}
+	opts.threshold, err = flags.GetFloat64("threshold")
+	return opts, err
+}
+
+// runBenchmarks runs go test with the benchmarks matching the pattern and
+// returns its output. The benchmarks read the puzzle data from --data-dir, and
+// missing data fails them rather than silently skipping
+func runBenchmarks(srcDir, pattern, packages string, count int) ([]byte, error) {
+	args := []string{"test", "-run", "^$", "-bench", pattern, "-benchmem", "-count", fmt.Sprint(count), packages}
+	fmt.Fprintln(os.Stderr, "Running go", args)
+
+	// The tests run in their package directory, so the data dir must be absolute
+	dataDir, err := filepath.Abs(cmn.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var output bytes.Buffer
+	goCmd := exec.Command("go", args...)
+	goCmd.Dir = srcDir
+	goCmd.Env = append(os.Environ(), cmn.DataDirEnvVar+"="+dataDir, cmn.RequireDataEnvVar+"=1")
+	goCmd.Stdout = &output
+	goCmd.Stderr = os.Stderr
+	if err := goCmd.Run(); err != nil {
+		os.Stderr.Write(output.Bytes())
+		return nil, fmt.Errorf("go test failed: %w", err)
+	}
+	return output.Bytes(), nil
+}
+
+// resolveSrcPath makes relative paths relative to the source directory
+func resolveSrcPath(srcDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(srcDir, path)
+}
+
+// PrintBenchComparisons writes a table of the comparisons and returns the
+// number of regressions
+func PrintBenchComparisons(w io.Writer, comparisons []*cmn.BenchComparison) int {
+	regressions := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tOLD NS/OP\tNEW NS/OP\tDELTA\tP\tSTATUS")
+	for _, comparison := range comparisons {
+		status := "~"
+		if comparison.Regression {
+			status = "REGRESSION"
+			regressions++
+		} else if comparison.Significant && comparison.Delta < 0 {
+			status = "improved"
+		} else if comparison.Significant {
+			status = "slower"
+		}
+		fmt.Fprintf(tw, "%s\t%.0f\t%.0f\t%+.2f%%\t%.3f\t%s\n",
+			comparison.Name, comparison.Old, comparison.New, comparison.Delta*100, comparison.P, status)
+	}
+	tw.Flush()
+	return regressions
+}
+
+func init() {
+	benchCmd.Flags().String("src", ".", "Root of the advent module source, containing go.mod")
+	benchCmd.Flags().String("bench", ".", "Regular expression selecting the benchmarks to run")
+	benchCmd.Flags().String("packages", "./cmd/...", "Packages to benchmark")
+	benchCmd.Flags().Int("count", 10, "Number of times to run each benchmark")
+	benchCmd.Flags().String("out", filepath.Join("bench", "latest.txt"), "File to store the results in, relative to --src")
+	benchCmd.Flags().String("baseline", filepath.Join("bench", "baseline.txt"), "Baseline results to compare against, relative to --src")
+	benchCmd.Flags().Bool("save-baseline", false, "Store the results as the new baseline instead of comparing")
+	benchCmd.Flags().Float64("alpha", 0.05, "Significance level of the comparison")
+	benchCmd.Flags().Float64("threshold", 0.05, "Minimum relative slowdown reported as a regression")
+}
@@ -31,11 +31,8 @@ package day1
 
 import (
 	"advent/cmn"
-	"fmt"
 	"math"
-	"regexp"
 	"sort"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
@@ -44,78 +41,57 @@ var LocationCheck = &cobra.Command{
 	Use:   "loc-check",
 	Short: "",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Do Stuff Here
-		if err := cmd.ParseFlags(args); err != nil {
-			fmt.Println(err)
-			return
-		}
-
-		puzzle, err := cmd.Flags().GetInt("puzzle-num")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler, err := cmn.NewHandlerE(cmd, cmn.WithArgs(args))
+		defer handler.Close()
 		if err != nil {
-			fmt.Println(err)
-			return
-		}
-
-		if puzzle == 1 {
-			if err := SolvePuzzleOne(); err != nil {
-				fmt.Println(err)
-			}
-		} else {
-
-			if err := SolvePuzzleTwo(); err != nil {
-				fmt.Println(err)
-			}
+			return handler.WriteRecord(err)
 		}
+		return handler.Solve()
 	},
 }
 
 func init() {
-	cmn.InitDailyCmd(LocationCheck, 1)
+	cmn.InitDailyCmd(LocationCheck, 1, SolvePuzzleOne, SolvePuzzleTwo)
 }
 
 // SolvePuzzleOne solves the first puzzle
-func SolvePuzzleOne() error {
-	handler := cmn.NewHandler()
-
-	defer handler.FileStream.Close()
-
+func SolvePuzzleOne(handler *cmn.AdventHandler) error {
+	endParse := handler.StartSpan("parse")
 	leftNums, rightNums, err := ParseP1Data(handler)
+	endParse()
 	if err != nil {
 		return err
 	}
 
+	defer handler.StartSpan("solve")()
+
 	totalDist := 0
 	for i, leftNum := range leftNums {
 		rightNum := rightNums[i]
 		dist := math.Abs(float64(leftNum - rightNum))
 		totalDist += int(dist)
 	}
-	fmt.Println("Total distance:", totalDist)
+	handler.Report("Total distance:", totalDist)
 	return nil
 }
 
+// LocationPair is a line of the puzzle data, a location ID from each list
+type LocationPair struct {
+	Left  int `field:"0"`
+	Right int `field:"1"`
+}
+
 // ParseP1Data parses the data for the first puzzle
 func ParseP1Data(handler *cmn.AdventHandler) (leftNums, rightNums []int, err error) {
+	pairs, err := cmn.ParseLines[LocationPair](handler)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	sepRe := regexp.MustCompile(`\s+`)
-	for handler.Scanner.Scan() {
-		line := handler.Scanner.Text()
-		numPair := sepRe.Split(line, -1)
-		if len(numPair) != 2 {
-			return nil, nil, fmt.Errorf("invalid data format:\n%v\n", line)
-		}
-		if leftNum, err := strconv.Atoi(numPair[0]); err != nil {
-			return nil, nil, err
-		} else {
-			leftNums = append(leftNums, leftNum)
-		}
-
-		if rightNum, err := strconv.Atoi(numPair[1]); err != nil {
-			return nil, nil, err
-		} else {
-			rightNums = append(rightNums, rightNum)
-		}
+	for _, pair := range pairs {
+		leftNums = append(leftNums, pair.Left)
+		rightNums = append(rightNums, pair.Right)
 	}
 
 	sort.Slice(leftNums, func(i, j int) bool {
@@ -130,23 +106,22 @@ func ParseP1Data(handler *cmn.AdventHandler) (leftNums, rightNums []int, err err
 }
 
 // SolvePuzzleTwo solves the second puzzle
-func SolvePuzzleTwo() error {
-	handler := cmn.NewHandler()
-	defer handler.FileStream.Close()
-
+func SolvePuzzleTwo(handler *cmn.AdventHandler) error {
+	endParse := handler.StartSpan("parse")
 	nums, numCounts, err := ParseP2Data(handler)
+	endParse()
 	if err != nil {
 		return err
 	}
 
+	defer handler.StartSpan("solve")()
+
 	totalScore := 0
 	for _, num := range nums {
-		if count, exists := numCounts[num]; exists {
-			totalScore += num * count
-		}
+		totalScore += num * numCounts.Count(num)
 	}
 
-	fmt.Printf("Total score: %v\n", totalScore)
+	handler.Report("Total score:", totalScore)
 
 	return nil
 }
@@ -154,9 +129,9 @@ func SolvePuzzleTwo() error {
 // ParseP2Data parses the data for the second puzzle
 // In this case, the goal is to check the number of times each leftNum appears
 // in rightNums, multiply the number by that amount, and add the total scores
-// So to parse the data right, I need to return leftNums and then a map[int]int
-// with the counts per leftNum
-func ParseP2Data(handler *cmn.AdventHandler) (leftNums []int, numCounts map[int]int, err error) {
+// So to parse the data right, I need to return leftNums and then a Counter of
+// the rightNums
+func ParseP2Data(handler *cmn.AdventHandler) (leftNums []int, numCounts cmn.Counter[int], err error) {
 	// to start, I can utilize the results from ParseP1Data still
 	var rightNums []int
 	leftNums, rightNums, err = ParseP1Data(handler)
@@ -164,13 +139,5 @@ func ParseP2Data(handler *cmn.AdventHandler) (leftNums []int, numCounts map[int]
 		return nil, nil, err
 	}
 
-	numCounts = map[int]int{}
-	for _, num := range rightNums {
-		if _, exists := numCounts[num]; !exists {
-			numCounts[num] = 1
-		} else {
-			numCounts[num]++
-		}
-	}
-	return leftNums, numCounts, nil
+	return leftNums, cmn.NewCounter(rightNums...), nil
 }
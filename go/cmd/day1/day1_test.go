@@ -0,0 +1,58 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package Name: day1
+	Description: Benchmarks for Day 1 of Advent of Code 2024
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package day1
+
+import (
+	"advent/cmn"
+	"advent/cmn/cmntest"
+	"testing"
+)
+
+func BenchmarkParseP1Data(b *testing.B) {
+	cmntest.Benchmark(b, 1, 1, func(handler *cmn.AdventHandler) error {
+		_, _, err := ParseP1Data(handler)
+		return err
+	})
+}
+
+func BenchmarkParseP2Data(b *testing.B) {
+	cmntest.Benchmark(b, 1, 2, func(handler *cmn.AdventHandler) error {
+		_, _, err := ParseP2Data(handler)
+		return err
+	})
+}
+
+func BenchmarkSolvePuzzleOne(b *testing.B) {
+	cmntest.Benchmark(b, 1, 1, SolvePuzzleOne)
+}
+
+func BenchmarkSolvePuzzleTwo(b *testing.B) {
+	cmntest.Benchmark(b, 1, 2, SolvePuzzleTwo)
+}
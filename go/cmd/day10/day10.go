@@ -37,8 +37,9 @@ var Day10Cmd = &cobra.Command{
 	Use:   "day10",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day10 called")
+		return nil
 	},
 }
\ No newline at end of file
@@ -37,8 +37,9 @@ var Day15Cmd = &cobra.Command{
 	Use:   "day15",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day15 called")
+		return nil
 	},
 }
\ No newline at end of file
@@ -37,8 +37,9 @@ var Day17Cmd = &cobra.Command{
 	Use:   "day17",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day17 called")
+		return nil
 	},
 }
\ No newline at end of file
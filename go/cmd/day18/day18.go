@@ -37,8 +37,9 @@ var Day18Cmd = &cobra.Command{
 	Use:   "day18",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day18 called")
+		return nil
 	},
 }
\ No newline at end of file
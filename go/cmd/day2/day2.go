@@ -31,10 +31,10 @@ package day2
 
 import (
 	"advent/cmn"
-	"fmt"
+	"context"
+	"log/slog"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/spf13/cobra"
 )
@@ -43,75 +43,49 @@ type Report struct {
 	Increasing *bool
 	Levels     []int
 	FailedOnce bool
+	Log        *slog.Logger // Log is the report's logger, slog.Default() if nil
 }
 
-var DebugEnabled = false
-
 var SafeReports = &cobra.Command{
 	Use:   "safe-reports",
 	Short: "",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Do Stuff Here
-		cmn.HandleErr(cmd.ParseFlags(args))
-
-		puzzle, err := cmd.Flags().GetInt("puzzle-num")
-		cmn.HandleErr(err)
-
-		async, err := cmd.Flags().GetBool("async")
-		cmn.HandleErr(err)
-
-		switch puzzle {
-		case 1:
-			if async {
-				cmn.HandleErr(SolvePuzzleOneAsync())
-			} else {
-				cmn.HandleErr(SolvePuzzleOneSync())
-			}
-		case 2:
-			cmn.HandleErr(SolvePuzzleTwo())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler, err := cmn.NewHandlerE(cmd, cmn.WithArgs(args))
+		defer handler.Close()
+		if err != nil {
+			return handler.WriteRecord(err)
 		}
-
+		return handler.Solve()
 	},
 }
 
 func init() {
-	cmn.InitDailyCmd(SafeReports, 2)
+	cmn.InitDailyCmd(SafeReports, 2, SolvePuzzleOne, SolvePuzzleTwo)
 	SafeReports.Flags().BoolP("async", "a", false, "Whether to solve using async methods")
-	SafeReports.Flags().BoolVarP(&DebugEnabled, "debug", "D", false, "Enable debug statements")
 }
 
-func Debug(args ...any) {
-	if DebugEnabled {
-		fmt.Println(args...)
-	}
-}
-
-func Debugf(fmtStr string, args ...any) {
-	if DebugEnabled {
-		fmt.Printf(fmtStr, args...)
+// logger returns the report's logger, falling back to the default logger
+func (r *Report) logger() *slog.Logger {
+	if r.Log == nil {
+		return slog.Default()
 	}
+	return r.Log
 }
 
 func (r *Report) CheckLevels(prev, cur *int) bool {
-	Debug("Checking levels", prev, cur)
 	if prev == nil || cur == nil {
 		return true
 	}
 
 	change, abs := cmn.DistInt(*cur, *prev)
-	Debug("change:", change, "abs:", abs)
 	if abs < 1 || abs > 3 {
+		cmn.Trace(r.logger(), "level change out of range", "prev", *prev, "cur", *cur, "change", change)
 		return false
 	}
 
 	increasing := change > 0
-	Debugf("Prev: %d, Cur: %d\nIncrease: %v, R.Increasing: %v", *prev, *cur, increasing, r.Increasing)
-	if r.Increasing != nil {
-		Debugf(", R.Increasing (val): %v\n", *r.Increasing)
-	} else {
-		Debugf("\n")
-	}
+	cmn.Trace(r.logger(), "checking levels", "prev", *prev, "cur", *cur, "increasing", increasing, "reportIncreasing", r.Increasing)
 
 	if r.Increasing == nil {
 		r.Increasing = &increasing
@@ -148,25 +122,25 @@ func (r *Report) IsSafe2() bool {
 
 		level := r.Levels[i]
 		nextLevel := r.Levels[i+1]
-		Debugf("\nlevel: %d, next: %d, i: %d\n", level, nextLevel, i)
+		cmn.Trace(r.logger(), "checking level", "level", level, "next", nextLevel, "i", i)
 
 		cur = &level
 		next = &nextLevel
 
 		if !r.CheckLevels(cur, next) {
-			Debug("Level failed, r.FailedOnce:", r.FailedOnce)
+			r.logger().Debug("level failed", "level", level, "next", nextLevel, "failedOnce", r.FailedOnce)
 			if !r.FailedOnce {
 				if i == len(r.Levels)-2 {
 					return true
 				}
 
 				r.FailedOnce = true
-				Debug("resetting values")
 				for idx := range r.Levels {
 
 					dampenedReport := &Report{
 						Levels:     cmn.RemFromSlice(r.Levels, idx),
 						FailedOnce: true,
+						Log:        r.Log,
 					}
 					if dampenedReport.IsSafe2() {
 						return true
@@ -191,37 +165,46 @@ func (r *Report) IsSafe2() bool {
 	return true
 }
 
-func NewReport(line string) (report *Report, err error) {
+func NewReport(line string, log *slog.Logger) (report *Report, err error) {
 
-	report = &Report{FailedOnce: false}
+	report = &Report{FailedOnce: false, Log: log}
 	parsedLine, _, _ := strings.Cut(line, "#")
 	levelStrs := strings.Split(strings.TrimSpace(parsedLine), " ")
 
+	offset := len(parsedLine) - len(strings.TrimLeft(parsedLine, " "))
 	for _, levelStr := range levelStrs {
 
 		if level, err := strconv.Atoi(levelStr); err != nil {
-			return report, &cmn.InvalidDataError{Line: line, Err: err}
+			return report, cmn.FieldDataError(line, offset, levelStr, "a level")
 		} else {
 			report.Levels = append(report.Levels, level)
 		}
+		offset += len(levelStr) + 1
 	}
 
 	return report, nil
 }
 
-func SolvePuzzleOneSync() error {
-	defer cmn.StartProfile("SolvePuzzleOneSync")()
+// SolvePuzzleOne solves the first puzzle, using the async solver if the async
+// flag is set
+func SolvePuzzleOne(handler *cmn.AdventHandler) error {
+	if cmn.GetFlagBool(handler.Cmd(), "async") {
+		return SolvePuzzleOneAsync(handler)
+	}
+	return SolvePuzzleOneSync(handler)
+}
 
-	handler := cmn.NewHandler()
+func SolvePuzzleOneSync(handler *cmn.AdventHandler) error {
+	defer handler.StartProfile("SolvePuzzleOneSync")()
 
-	defer handler.Close()
 	safeCount := 0
+	reportLog := handler.ComponentLog("report")
 
-	for handler.Scanner.Scan() {
-		line := handler.Scanner.Text()
-		report, err := NewReport(line)
+	for handler.Scan() {
+		line := handler.Text()
+		report, err := NewReport(line, reportLog)
 		if err != nil {
-			return err
+			return handler.DataError(err)
 		}
 
 		safe := report.IsSafe()
@@ -230,85 +213,69 @@ func SolvePuzzleOneSync() error {
 			safeCount++
 		}
 		if handler.IsSample {
-			fmt.Printf("Report: `%v` is [%v]\n", line, safe)
+			handler.Printf("Report: `%v` is [%v]\n", line, safe)
 		}
 
 	}
 
-	fmt.Println("Puzzle 1 Safe count:", safeCount)
+	handler.Report("Puzzle 1 Safe count:", safeCount)
 	return nil
 }
 
-func SolvePuzzleOneAsync() error {
-	defer cmn.StartProfile("SolvePuzzleOneAsync")()
-
-	handler := cmn.NewHandler()
+// SolvePuzzleOneAsync checks the reports concurrently
+func SolvePuzzleOneAsync(handler *cmn.AdventHandler) error {
+	defer handler.StartProfile("SolvePuzzleOneAsync")()
 
-	defer handler.Close()
-	safeCount := 0
-
-	numWorkers := 32
-	var wg sync.WaitGroup
-
-	reportChan := make(chan string, numWorkers)
+	reportLog := handler.ComponentLog("report")
+	progress := handler.Progress("reports", 0)
+	defer progress.Done()
 
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for reportLine := range reportChan {
-				report, err := NewReport(reportLine)
-				cmn.HandleErr(err)
-
-				if report.IsSafe() {
-					safeCount++
-				}
+	safeCount, err := cmn.MapReduceLines(handler.Context(), handler,
+		func(ctx context.Context, line string) (bool, error) {
+			report, err := NewReport(line, reportLog)
+			if err != nil {
+				return false, err
+			}
+			return report.IsSafe(), nil
+		},
+		func(count int, safe bool) int {
+			if safe {
+				count++
 			}
-		}()
+			return count
+		}, 0, cmn.WithWorkers(32), cmn.WithProgress(progress))
+	if err != nil {
+		return err
 	}
 
-	go func() {
-		for handler.Scan() {
-			reportChan <- handler.Text()
-		}
-		close(reportChan)
-	}()
-
-	wg.Wait()
-
-	fmt.Println("Puzzle 1 Safe count:", safeCount)
+	handler.Report("Puzzle 1 Safe count:", safeCount)
 	return nil
 }
 
-func SolvePuzzleTwo() error {
-	defer cmn.StartProfile("SolvePuzzleTwo")()
+func SolvePuzzleTwo(handler *cmn.AdventHandler) error {
+	defer handler.StartProfile("SolvePuzzleTwo")()
 
-	handler := cmn.NewHandler()
-
-	defer handler.Close()
 	safeCount := 0
+	reportLog := handler.ComponentLog("report")
 
-	for handler.Scanner.Scan() {
-		line := handler.Scanner.Text()
-		report, err := NewReport(line)
+	for handler.Scan() {
+		line := handler.Text()
+		report, err := NewReport(line, reportLog)
 		if err != nil {
-			return err
+			return handler.DataError(err)
 		}
 
 		safe := report.IsSafe2()
-		Debug("\nLine:", line, "\nLevels:", report.Levels, "\nSafe:", safe)
-		if DebugEnabled {
-			cmn.GetInput("Continue?")
-		}
+		handler.Log.Debug("checked report", "line", line, "levels", report.Levels, "safe", safe)
 		if safe {
 			safeCount++
 		}
 		if handler.IsSample {
-			fmt.Printf("Report: `%v` is [%v]\n", line, safe)
+			handler.Printf("Report: `%v` is [%v]\n", line, safe)
 		}
 
 	}
 
-	fmt.Println("Puzzle 2 Safe count:", safeCount)
+	handler.Report("Puzzle 2 Safe count:", safeCount)
 	return nil
 }
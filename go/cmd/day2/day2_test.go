@@ -0,0 +1,115 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package Name: day2
+	Description: Tests and benchmarks for Day 2 of Advent of Code 2024
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package day2
+
+import (
+	"advent/cmn"
+	"advent/cmn/cmntest"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const reportData = `7 6 4 2 1
+1 2 7 8 9
+9 7 6 2 1
+1 3 2 4 5
+8 6 4 4 1
+1 3 6 7 9
+`
+
+func TestSolvePuzzleOneAsyncMatchesSync(t *testing.T) {
+	cmntest.Quiet(t)
+	data := []byte(strings.Repeat(reportData, 200))
+
+	sync := cmntest.Handler(t, 2, 1, data, SolvePuzzleOneSync)
+	if err := sync.Solve(); err != nil {
+		t.Fatal(err)
+	}
+	async := cmntest.Handler(t, 2, 1, data, SolvePuzzleOneAsync)
+	if err := async.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sync.Answer != "400" || async.Answer != sync.Answer {
+		t.Errorf("expected 400 safe reports, sync: %v, async: %v", sync.Answer, async.Answer)
+	}
+}
+
+func TestSolvePuzzleOneAsyncInvalidData(t *testing.T) {
+	cmntest.Quiet(t)
+	data := []byte(reportData + "1 2 x\n" + reportData)
+
+	handler := cmntest.Handler(t, 2, 1, data, SolvePuzzleOneAsync)
+	var dataErr *cmn.InvalidDataError
+	if err := handler.Solve(); !errors.As(err, &dataErr) {
+		t.Fatalf("expected an InvalidDataError, got %v", err)
+	}
+	if dataErr.LineNum != 7 || dataErr.Col != 5 {
+		t.Errorf("expected the error at line 7 column 5, got %d:%d", dataErr.LineNum, dataErr.Col)
+	}
+}
+
+func TestReportWithoutLogger(t *testing.T) {
+	safe := &Report{Levels: []int{1, 3, 6, 7, 9}}
+	if !safe.IsSafe() || !(&Report{Levels: []int{1, 3, 6, 7, 9}}).IsSafe2() {
+		t.Error("expected the report to be safe")
+	}
+	dampened := &Report{Levels: []int{1, 3, 2, 4, 5}}
+	if !dampened.IsSafe2() {
+		t.Error("expected the report to be safe after removing a level")
+	}
+	if (&Report{Levels: []int{9, 7, 6, 2, 1}}).IsSafe2() {
+		t.Error("expected the report to be unsafe")
+	}
+}
+
+func BenchmarkNewReport(b *testing.B) {
+	cmntest.Benchmark(b, 2, 1, func(handler *cmn.AdventHandler) error {
+		for handler.Scan() {
+			if _, err := NewReport(handler.Text(), handler.Log); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func BenchmarkSolvePuzzleOneSync(b *testing.B) {
+	cmntest.Benchmark(b, 2, 1, SolvePuzzleOneSync)
+}
+
+func BenchmarkSolvePuzzleOneAsync(b *testing.B) {
+	cmntest.Benchmark(b, 2, 1, SolvePuzzleOneAsync)
+}
+
+func BenchmarkSolvePuzzleTwo(b *testing.B) {
+	cmntest.Benchmark(b, 2, 2, SolvePuzzleTwo)
+}
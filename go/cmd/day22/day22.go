@@ -37,8 +37,9 @@ var Day22Cmd = &cobra.Command{
 	Use:   "day22",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day22 called")
+		return nil
 	},
 }
\ No newline at end of file
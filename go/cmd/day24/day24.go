@@ -37,8 +37,9 @@ var Day24Cmd = &cobra.Command{
 	Use:   "day24",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day24 called")
+		return nil
 	},
 }
\ No newline at end of file
@@ -37,8 +37,9 @@ var Day25Cmd = &cobra.Command{
 	Use:   "day25",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day25 called")
+		return nil
 	},
 }
\ No newline at end of file
@@ -31,7 +31,6 @@ package day3
 
 import (
 	"advent/cmn"
-	"fmt"
 	"regexp"
 	"strconv"
 
@@ -45,17 +44,18 @@ var MullItCmd = &cobra.Command{
 	Use:   "mull-it",
 	Short: "",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		handler := cmn.NewHandler(
-			cmn.WithArgs(args),
-			cmn.WithSolvers(SolvePuzzleOne, SolvePuzzleTwo),
-		)
-		cmn.HandleErr(handler.Solve())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler, err := cmn.NewHandlerE(cmd, cmn.WithArgs(args))
+		defer handler.Close()
+		if err != nil {
+			return handler.WriteRecord(err)
+		}
+		return handler.Solve()
 	},
 }
 
 func init() {
-	cmn.InitDailyCmd(MullItCmd, 3)
+	cmn.InitDailyCmd(MullItCmd, 3, SolvePuzzleOne, SolvePuzzleTwo)
 }
 
 func extractMatchInts(text string) (values [][]int, err error) {
@@ -84,7 +84,7 @@ func extractMatchInts(text string) (values [][]int, err error) {
 }
 
 func SolvePuzzleOne(handler *cmn.AdventHandler) error {
-	defer cmn.StartProfile("SolvePuzzleOne")()
+	defer handler.StartProfile("SolvePuzzleOne")()
 
 	total := 0
 	for handler.Scan() {
@@ -99,18 +99,19 @@ func SolvePuzzleOne(handler *cmn.AdventHandler) error {
 		}
 	}
 
-	fmt.Println("Total:", total)
+	handler.Report("Total:", total)
 	return nil
 }
 
-var disabled = false
-
-func extractMatchInts2(text string) (values [][]int, err error) {
+// extractMatchInts2 extracts the enabled mul instructions of the line. The
+// do() and don't() instructions carry over between lines, so the state at the
+// start of the line is passed in and the state at its end is returned
+func extractMatchInts2(handler *cmn.AdventHandler, text string, disabled bool) (values [][]int, stillDisabled bool, err error) {
 	matches := mulRe2.FindAllStringSubmatch(text, -1)
 
 	for _, match := range matches {
 		if len(match) != 3 {
-			fmt.Println("bad value")
+			handler.Log.Warn("unexpected match", "match", match)
 		}
 		if len(match) == 3 {
 			switch match[0] {
@@ -127,14 +128,14 @@ func extractMatchInts2(text string) (values [][]int, err error) {
 
 				a, err := strconv.Atoi(aStr)
 				if err != nil {
-					fmt.Println("a:", aStr, "b:", bStr, "text:", text, "match:", match)
-					return nil, err
+					handler.Log.Debug("bad multiplicand", "a", aStr, "b", bStr, "match", match)
+					return nil, disabled, handler.DataError(err)
 				}
 
 				b, err := strconv.Atoi(bStr)
 				if err != nil {
-					fmt.Println("a:", aStr, "b:", bStr, "text:", text)
-					return nil, err
+					handler.Log.Debug("bad multiplier", "a", aStr, "b", bStr, "match", match)
+					return nil, disabled, handler.DataError(err)
 				}
 
 				values = append(values, []int{a, b})
@@ -142,17 +143,20 @@ func extractMatchInts2(text string) (values [][]int, err error) {
 		}
 	}
 
-	return values, nil
+	return values, disabled, nil
 }
 
 func SolvePuzzleTwo(handler *cmn.AdventHandler) error {
-	defer cmn.StartProfile("SolvePuzzleTwo")()
+	defer handler.StartProfile("SolvePuzzleTwo")()
 
 	total := 0
+	disabled := false
 
 	for handler.Scan() {
 		line := handler.Text()
-		values, err := extractMatchInts2(line)
+		var values [][]int
+		var err error
+		values, disabled, err = extractMatchInts2(handler, line, disabled)
 		if err != nil {
 			return err
 		}
@@ -161,7 +165,7 @@ func SolvePuzzleTwo(handler *cmn.AdventHandler) error {
 			total += (pair[0] * pair[1])
 		}
 	}
-	fmt.Println("Total:", total)
+	handler.Report("Total:", total)
 
 	return nil
 }
@@ -31,8 +31,7 @@ package day4
 
 import (
 	"advent/cmn"
-	"fmt"
-	"strings"
+	"advent/cmn/grid"
 
 	"github.com/spf13/cobra"
 )
@@ -41,271 +40,120 @@ var WordSearch = &cobra.Command{
 	Use:   "word-search",
 	Short: "",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		handler := cmn.NewHandler(
-			cmn.WithArgs(args),
-			cmn.WithSolvers(SolvePuzzleOne, SolvePuzzleTwo),
-		)
-		cmn.HandleErr(handler.Solve())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler, err := cmn.NewHandlerE(cmd, cmn.WithArgs(args))
+		defer handler.Close()
+		if err != nil {
+			return handler.WriteRecord(err)
+		}
+		return handler.Solve()
 	},
 }
 
 func init() {
-	cmn.InitDailyCmd(WordSearch, 4)
-}
-
-type Direction int
-
-const (
-	NorthWest Direction = iota
-	North
-	NorthEast
-	West
-	East
-	SouthWest
-	South
-	SouthEast
-)
-
-var CharMap = map[string]string{
-	"X": "M",
-	"M": "A",
-	"A": "S",
-}
-
-var FoundWords = cmn.NewSet[string]()
-
-type CharNode struct {
-	X       int
-	Y       int
-	Value   string
-	ID      string
-	NodeMap *[][]*CharNode
-
-	Neighbors map[Direction]*CharNode
+	cmn.InitDailyCmd(WordSearch, 4, SolvePuzzleOne, SolvePuzzleTwo)
 }
 
-func (n *CharNode) Print() {
-	dirs := [][]Direction{
-		{NorthWest, North, NorthEast},
-		{West, -1, East},
-		{SouthWest, South, SouthEast},
-	}
+// Word is the word searched for in the first puzzle
+const Word = "XMAS"
 
-	for _, row := range dirs {
-		for _, dir := range row {
-			if dir == -1 {
-				fmt.Printf("[%s:'%s']", n.ID, n.Value)
-			} else {
-				node := n.Neighbors[dir]
-				nodeStr := "[-,-:'-']"
-				if node != nil {
-					nodeStr = fmt.Sprintf("[%s:'%s']", node.ID, node.Value)
-				}
-				fmt.Printf("%s", nodeStr)
+// CountWords counts the occurrences of the word spelled out from the start
+// point in each of the 8 directions
+func CountWords(letters *grid.Grid[rune], start grid.Point, word string) int {
+	count := 0
+	for _, dir := range grid.AllDirections {
+		found := true
+		for i, char := range []rune(word) {
+			if letters.At(start.Move(dir, i)) != char {
+				found = false
+				break
 			}
 		}
-		fmt.Println()
-	}
-
-}
-
-func (n *CharNode) FindNeighbors() {
-	var direction Direction
-
-	for yOffset := -1; yOffset <= 1; yOffset++ {
-		for xOffset := -1; xOffset <= 1; xOffset++ {
-			if yOffset == 0 && xOffset == 0 {
-				continue
-			}
-			x := n.X + xOffset
-			y := n.Y + yOffset
-			n.Neighbors[direction] = FindNode(x, y, *n.NodeMap)
-			direction++
+		if found {
+			count++
 		}
 	}
+	return count
 }
 
-func (n *CharNode) SearchChar(direction Direction, char string, path *[]string) bool {
-	if char != n.Value {
-		return false
-	}
-
-	if char == "S" {
-		return true
+// CheckX checks if both diagonals through the center spell MAS in either
+// direction
+func CheckX(letters *grid.Grid[rune], center grid.Point) bool {
+	crossmatch := map[rune]rune{
+		'S': 'M',
+		'M': 'S',
 	}
 
-	if node, ok := n.Neighbors[direction]; ok && node != nil {
-		target, exists := CharMap[n.Value]
-		if !exists {
-			return false
-		}
-		*path = append(*path, n.ID)
-		return node.SearchChar(direction, target, path)
-	}
-	return false
-}
-
-func (n *CharNode) SearchNeighbors() {
-	target := CharMap[n.Value]
-	for direction, node := range n.Neighbors {
-		if node != nil {
-			nodePath := []string{n.ID}
-			if node.SearchChar(direction, target, &nodePath) {
-				pathStr := strings.Join(nodePath, "_")
-				FoundWords.Add(pathStr)
-			}
-		}
-	}
-}
-
-func (n *CharNode) CheckX() bool {
-	pairs := [][]*CharNode{
-		{n.Neighbors[NorthWest], n.Neighbors[SouthEast]},
-		{n.Neighbors[NorthEast], n.Neighbors[SouthWest]},
-	}
-
-	crossmatch := map[string]string{
-		"S": "M",
-		"M": "S",
-	}
-
-	for _, pair := range pairs {
-		p1, p2 := pair[0], pair[1]
-		if p1 == nil || p2 == nil {
-			return false
-		}
-		if match, ok := crossmatch[p1.Value]; !ok || match != p2.Value {
+	for _, dir := range []grid.Direction{grid.NorthWest, grid.NorthEast} {
+		p1, p2 := letters.At(center.Add(dir)), letters.At(center.Add(dir.Opposite()))
+		if match, ok := crossmatch[p1]; !ok || match != p2 {
 			return false
 		}
 	}
 	return true
 }
 
-func FindNode(x, y int, nodeMap [][]*CharNode) *CharNode {
-	if y < 0 || x < 0 || y >= len(nodeMap) {
-		return nil
-	}
-	for row, nodes := range nodeMap {
-		if row == y {
-			if x >= len(nodes) {
-				return nil
-			}
-			return nodes[x]
-		}
-	}
-	return nil
-}
-
-func NewNode(x, y int, value string) *CharNode {
-	return &CharNode{
-		X:         x,
-		Y:         y,
-		Value:     value,
-		ID:        fmt.Sprintf("%d,%d", x, y),
-		Neighbors: map[Direction]*CharNode{},
-		NodeMap:   &[][]*CharNode{},
+// parseAnchors reads the letter grid and finds the points of the anchor letter
+func parseAnchors(handler *cmn.AdventHandler, anchor rune) (*grid.Grid[rune], []grid.Point, error) {
+	letters, err := grid.ParseRunes(handler)
+	if err != nil {
+		return nil, nil, err
 	}
+	return letters, letters.FindAll(func(r rune) bool { return r == anchor }), nil
 }
 
-func NewNodeMap(values [][]string, anchorChar string) (nodeMap [][]*CharNode, anchorNodes []*CharNode) {
-	nodeMap = make([][]*CharNode, len(values))
-	anchorNodes = []*CharNode{}
-	for y, row := range values {
-		nodeRow := make([]*CharNode, len(row))
-		for x, value := range row {
-			node := NewNode(x, y, value)
-			nodeRow[x] = node
-			if value == anchorChar {
-				anchorNodes = append(anchorNodes, node)
-			}
-		}
-		nodeMap[y] = nodeRow
-	}
-
-	return nodeMap, anchorNodes
-}
-
-func InitNeighbors(nodeMap [][]*CharNode) {
-	//for y := 0; y < len(nodeMap); y++ {
-	for _, nodes := range nodeMap {
-		//nodes := nodeMap[y]
-		//for x := 0; x < len(nodes); x++ {
-		for _, node := range nodes {
-			//node := nodes[x]
-			node.NodeMap = &nodeMap
-			node.FindNeighbors()
-		}
-	}
+func ParseP1Data(handler *cmn.AdventHandler) (*grid.Grid[rune], []grid.Point, error) {
+	return parseAnchors(handler, 'X')
 }
 
-func ParseP1Data(handler *cmn.AdventHandler) []*CharNode {
-	charValues := [][]string{}
-	for handler.Scan() {
-		line := handler.Text()
-		row := make([]string, len(line))
-		for i, char := range line {
-			row[i] = string(char)
-		}
-		charValues = append(charValues, row)
+func SolvePuzzleOne(handler *cmn.AdventHandler) error {
+	defer handler.StartProfile("SolvePuzzleOne")()
+	endParse := handler.StartSpan("parse")
+	letters, xPoints, err := ParseP1Data(handler)
+	endParse()
+	if err != nil {
+		return err
 	}
+	defer handler.StartSpan("solve")()
 
-	charNodes, xNodes := NewNodeMap(charValues, "X")
-
-	InitNeighbors(charNodes)
-
-	return xNodes
-}
-
-func SolvePuzzleOne(handler *cmn.AdventHandler) error {
-	defer cmn.StartProfile("SolvePuzzleOne")()
-	xNodes := ParseP1Data(handler)
-	for _, node := range xNodes {
-		node.SearchNeighbors()
+	count := 0
+	for _, point := range xPoints {
+		count += CountWords(letters, point, Word)
 	}
 
-	fmt.Println("Found words:", FoundWords.Len())
+	handler.Report("Found words:", count)
 
 	return nil
 }
 
-func ParseP2Data(handler *cmn.AdventHandler) []*CharNode {
-	charValues := [][]string{}
-	for handler.Scan() {
-		line := handler.Text()
-		row := make([]string, len(line))
-		for i, char := range line {
-			row[i] = string(char)
-		}
-		charValues = append(charValues, row)
+func ParseP2Data(handler *cmn.AdventHandler) (*grid.Grid[rune], []grid.Point, error) {
+	letters, aPoints, err := parseAnchors(handler, 'A')
+	if err == nil && handler.IsSample {
+		handler.Printf("%s", letters)
 	}
-
-	charNodes, xNodes := NewNodeMap(charValues, "A")
-
-	if handler.IsSample {
-		for _, row := range charValues {
-			fmt.Println(row)
-		}
-	}
-
-	InitNeighbors(charNodes)
-
-	return xNodes
+	return letters, aPoints, err
 }
 
 func SolvePuzzleTwo(handler *cmn.AdventHandler) error {
-	defer cmn.StartProfile("SolvePuzzleOne")()
+	defer handler.StartProfile("SolvePuzzleTwo")()
+
+	endParse := handler.StartSpan("parse")
+	letters, aPoints, err := ParseP2Data(handler)
+	endParse()
+	if err != nil {
+		return err
+	}
+	defer handler.StartSpan("solve")()
 
-	aNodes := ParseP2Data(handler)
-	fmt.Println(len(aNodes))
+	handler.Println(len(aPoints))
 	count := 0
-	for _, node := range aNodes {
-		if node.CheckX() {
+	for _, point := range aPoints {
+		if CheckX(letters, point) {
 			count++
 		}
 	}
 
-	fmt.Println("Found:", count)
+	handler.Report("Found:", count)
 
 	return nil
 }
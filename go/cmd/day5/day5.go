@@ -31,9 +31,8 @@ package day5
 
 import (
 	"advent/cmn"
-	"fmt"
-	"strconv"
-	"strings"
+	"advent/cmn/graph"
+	"log/slog"
 
 	"github.com/spf13/cobra"
 )
@@ -42,83 +41,95 @@ var PrintItCmd = &cobra.Command{
 	Use:   "print-it",
 	Short: "",
 	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		handler := cmn.NewHandler(
-			cmn.WithSolvers(SolvePuzzleOne, SolvePuzzleTwo),
-			cmn.WithArgs(args),
-		)
-		cmn.HandleErr(handler.Solve())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		handler, err := cmn.NewHandlerE(cmd, cmn.WithArgs(args))
+		defer handler.Close()
+		if err != nil {
+			return handler.WriteRecord(err)
+		}
+		return handler.Solve()
 	},
 }
 
 func init() {
-	cmn.InitDailyCmd(PrintItCmd, 5)
+	cmn.InitDailyCmd(PrintItCmd, 5, SolvePuzzleOne, SolvePuzzleTwo)
 }
 
-type OrderCheckerP1 struct {
-	CheckedPages *cmn.Set[string]
-	OrderMap     map[string][]string
+// OrderChecker checks the page order of the manuals against the page ordering
+// rules, each rule is an edge from the page that must be printed first
+type OrderChecker struct {
+	Rules *graph.Digraph[int]
 
-	Scan   func() bool
-	Text   func() string
-	Debug  func(args ...any)
-	Debugf func(str string, args ...any)
+	Handler *cmn.AdventHandler
+	Log     *slog.Logger
 }
 
-func (o *OrderCheckerP1) CheckPage(page string) bool {
-	o.Debug("\nChecking page:", page)
-	if afterPages, exists := o.OrderMap[page]; exists {
-		o.Debug("After pages:", afterPages)
-		o.Debug("Checked Pages:", o.CheckedPages.Values())
-		for _, afterPage := range afterPages {
-			if o.CheckedPages.Contains(afterPage) {
-				o.Debugf("Page `%s` failed\n", page)
-				return false
-			}
-		}
-	}
-	o.CheckedPages.Add(page)
-	o.Debugf("Page `%s` passed\n", page)
-	return true
+// CheckManual checks that no rule between the manual's pages is broken
+func (o *OrderChecker) CheckManual(pages []int) bool {
+	ordered := o.Rules.IsOrdered(pages)
+	o.Log.Debug("checked manual", "pages", pages, "ordered", ordered)
+	return ordered
 }
 
-func (o *OrderCheckerP1) CheckManual(pages []string) bool {
-	o.CheckedPages = cmn.NewSet[string]()
-	for _, page := range pages {
-		if !o.CheckPage(page) {
-			return false
-		}
-	}
-	return true
+// FixManual sorts the manual's pages using only the rules between them
+func (o *OrderChecker) FixManual(pages []int) ([]int, error) {
+	fixed, err := o.Rules.SortSubset(pages)
+	cmn.Trace(o.Log, "fixed manual", "pages", pages, "fixed", fixed)
+	return fixed, err
 }
 
-func (o *OrderCheckerP1) InspectManuals() int {
+// InspectManuals sums the middle pages of the correctly ordered manuals, or of
+// the incorrectly ordered manuals once they've been fixed
+func (o *OrderChecker) InspectManuals(fix bool) (int, error) {
 	total := 0
-	for o.Scan() {
-		line := o.Text()
-		pages := strings.Split(line, ",")
-		if o.CheckManual(pages) {
-			o.Debug("Manual Passed:", pages)
-			midIdx := (len(pages) / 2)
-			middle, err := strconv.Atoi(pages[midIdx])
-			cmn.HandleErr(err)
-			o.Debugf("Len: %d, midIdx: %d, midPage: %d\n", len(pages), midIdx, middle)
-			total += middle
+	for o.Handler.Scan() {
+		pages, err := ParsePages(o.Handler.Text())
+		if err != nil {
+			return total, o.Handler.DataError(err)
+		}
+
+		if o.CheckManual(pages) == fix {
+			continue
 		}
+		if fix {
+			if pages, err = o.FixManual(pages); err != nil {
+				return total, err
+			}
+		}
+
+		midIdx := len(pages) / 2
+		o.Log.Debug("manual counted", "pages", pages, "midIdx", midIdx, "midPage", pages[midIdx])
+		total += pages[midIdx]
 	}
 
-	return total
+	return total, o.Handler.Scanner.Err()
+}
+
+// Rule is a page ordering rule, Before must be printed before After
+type Rule struct {
+	Before int `field:"0"`
+	After  int `field:"1"`
+}
+
+// Manual is the pages of a manual in print order
+type Manual struct {
+	Pages []int `field:"0:"`
 }
 
-func NewOrderCheckerP1(h *cmn.AdventHandler) *OrderCheckerP1 {
-	o := &OrderCheckerP1{
-		CheckedPages: cmn.NewSet[string](),
-		OrderMap:     map[string][]string{},
+// ParsePages parses a manual's comma separated page numbers
+func ParsePages(line string) ([]int, error) {
+	var manual Manual
+	err := cmn.ParseFields(line, &manual)
+	return manual.Pages, err
+}
+
+// NewOrderChecker parses the page ordering rules, up to the first blank line
+func NewOrderChecker(h *cmn.AdventHandler) (*OrderChecker, error) {
+	o := &OrderChecker{
+		Rules: graph.NewDigraph[int](),
 
-		Scan:   h.Scan,
-		Text:   h.Text,
-		Debug:  h.Debug,
-		Debugf: h.Debugf,
+		Handler: h,
+		Log:     h.ComponentLog("checker"),
 	}
 
 	for h.Scan() {
@@ -126,36 +137,57 @@ func NewOrderCheckerP1(h *cmn.AdventHandler) *OrderCheckerP1 {
 		if line == "" {
 			break
 		}
-		pageX, pageY, ok := strings.Cut(line, "|")
-		if !ok {
-			cmn.HandleErr(&cmn.InvalidDataError{Line: line})
+		var rule Rule
+		if err := cmn.ParseFields(line, &rule); err != nil {
+			return nil, h.DataError(err)
+		}
+		o.Rules.AddEdge(rule.Before, rule.After)
+	}
+
+	if o.Handler.IsSample {
+		for _, page := range o.Rules.Nodes() {
+			h.Printf("[%d]: %v\n", page, o.Rules.Successors(page))
 		}
-		o.OrderMap[pageX] = append(o.OrderMap[pageX], pageY)
 	}
 
-	return o
+	return o, nil
 }
 
-func SolvePuzzleOne(handler *cmn.AdventHandler) error {
-	defer cmn.StartProfile("SolvePuzzleOne")()
+// solve parses the rules and sums the middle pages of the manuals
+func solve(handler *cmn.AdventHandler, fix bool) (int, error) {
+	endParse := handler.StartSpan("parse")
+	orderChecker, err := NewOrderChecker(handler)
+	endParse()
+	if err != nil {
+		return 0, err
+	}
 
-	orderChecker := NewOrderCheckerP1(handler)
+	defer handler.StartSpan("solve")()
+	return orderChecker.InspectManuals(fix)
+}
 
-	if handler.IsSample {
-		for key, value := range orderChecker.OrderMap {
-			fmt.Printf("[%s]: %v\n", key, value)
-		}
-	}
+func SolvePuzzleOne(handler *cmn.AdventHandler) error {
+	defer handler.StartProfile("SolvePuzzleOne")()
 
-	goodManuals := orderChecker.InspectManuals()
+	goodManuals, err := solve(handler, false)
+	if err != nil {
+		return err
+	}
 
-	fmt.Println("Good manual score:", goodManuals)
+	handler.Report("Good manual score:", goodManuals)
 
 	return nil
 }
 
 func SolvePuzzleTwo(handler *cmn.AdventHandler) error {
-	defer cmn.StartProfile("SolvePuzzleTwo")()
+	defer handler.StartProfile("SolvePuzzleTwo")()
+
+	fixedManuals, err := solve(handler, true)
+	if err != nil {
+		return err
+	}
+
+	handler.Report("Fixed manual score:", fixedManuals)
 
 	return nil
 }
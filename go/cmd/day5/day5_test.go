@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package Name: day5
+	Description: Benchmarks for Day 5 of Advent of Code 2024
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package day5
+
+import (
+	"advent/cmn"
+	"advent/cmn/cmntest"
+	"advent/cmn/graph"
+	"errors"
+	"testing"
+)
+
+const manualData = `47|53
+97|13
+97|61
+97|47
+75|29
+61|13
+75|53
+29|13
+97|29
+53|29
+61|53
+97|53
+61|29
+47|13
+75|47
+97|75
+47|61
+75|61
+47|29
+75|13
+53|13
+
+75,47,61,53,29
+97,61,53,29,13
+75,29,13
+75,97,47,61,53
+61,13,29
+97,13,75,29,47
+`
+
+func TestSolveSample(t *testing.T) {
+	cmntest.Quiet(t)
+	tests := map[int]string{1: "143", 2: "123"}
+	for puzzleNum, expected := range tests {
+		handler := cmntest.Handler(t, 5, puzzleNum, []byte(manualData), SolvePuzzleOne, SolvePuzzleTwo)
+		if err := handler.Solve(); err != nil {
+			t.Fatal(err)
+		}
+		if handler.Answer != expected {
+			t.Errorf("puzzle %d answer = %s, want %s", puzzleNum, handler.Answer, expected)
+		}
+	}
+}
+
+func TestFixManualCycle(t *testing.T) {
+	cmntest.Quiet(t)
+	data := []byte("1|2\n2|3\n3|1\n\n3,2,1\n")
+
+	handler := cmntest.Handler(t, 5, 2, data, SolvePuzzleOne, SolvePuzzleTwo)
+	err := handler.Solve()
+	var cycleErr *graph.CycleError[int]
+	if !errors.As(err, &cycleErr) || len(cycleErr.Cycle) == 0 {
+		t.Fatalf("expected a CycleError, got %v", err)
+	}
+}
+
+func BenchmarkNewOrderChecker(b *testing.B) {
+	cmntest.Benchmark(b, 5, 1, func(handler *cmn.AdventHandler) error {
+		_, err := NewOrderChecker(handler)
+		return err
+	})
+}
+
+func BenchmarkSolvePuzzleOne(b *testing.B) {
+	cmntest.Benchmark(b, 5, 1, SolvePuzzleOne)
+}
+
+func BenchmarkSolvePuzzleTwo(b *testing.B) {
+	cmntest.Benchmark(b, 5, 2, SolvePuzzleTwo)
+}
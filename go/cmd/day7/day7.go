@@ -37,8 +37,9 @@ var Day7Cmd = &cobra.Command{
 	Use:   "day7",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day7 called")
+		return nil
 	},
 }
\ No newline at end of file
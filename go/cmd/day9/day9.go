@@ -37,8 +37,9 @@ var Day9Cmd = &cobra.Command{
 	Use:   "day9",
 	Short: "",
 	Long: ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Do Stuff Here
 		fmt.Println("day9 called")
+		return nil
 	},
 }
\ No newline at end of file
@@ -0,0 +1,94 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// fetchCmd downloads the puzzle inputs for the given days
+var fetchCmd = &cobra.Command{
+	Use:   "fetch <day>...",
+	Short: "Download the puzzle input for one or more days",
+	Long: `Downloads the puzzle input for each day to the day's input.txt in the data
+directory. Inputs that have already been downloaded are never fetched again.
+
+The session token is read from $` + cmn.SessionEnvVar + ` or the ` + cmn.SessionConfigFile + ` file in
+the user's config directory, and the site can be replaced with $` + cmn.BaseURLEnvVar + `
+or the --base-url flag.`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days := make([]int, len(args))
+		for i, arg := range args {
+			day, err := cmn.ParseDay(arg)
+			if err != nil {
+				return err
+			}
+			days[i] = day
+		}
+
+		client, err := newClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		for _, day := range days {
+			path, fetched, err := client.DownloadInput(day)
+			if err != nil {
+				return err
+			}
+			if fetched {
+				fmt.Printf("Day %d: saved input to %s\n", day, path)
+			} else {
+				fmt.Printf("Day %d: input already exists at %s\n", day, path)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	addClientFlags(fetchCmd)
+}
+
+// addClientFlags adds the flags used to configure the site client
+func addClientFlags(cmd *cobra.Command) {
+	cmd.Flags().String("base-url", "", "Base URL of the site, defaults to $"+cmn.BaseURLEnvVar+" or "+cmn.DefaultBaseURL)
+	cmd.Flags().Duration("rate-limit", cmn.DefaultRateLimit, "Minimum time between requests to the site")
+}
+
+// newClient creates a site client from the command's flags
+func newClient(cmd *cobra.Command) (*cmn.Client, error) {
+	baseURL, err := cmd.Flags().GetString("base-url")
+	if err != nil {
+		return nil, err
+	}
+	rateLimit, err := cmd.Flags().GetDuration("rate-limit")
+	if err != nil {
+		return nil, err
+	}
+	return cmn.NewClient(cmn.WithBaseURL(baseURL), cmn.WithRateLimit(rateLimit)), nil
+}
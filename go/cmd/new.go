@@ -0,0 +1,227 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// scaffoldTemplates is parsed from the embedded day templates
+var scaffoldTemplates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// scaffoldData holds the values used to render the day templates
+type scaffoldData struct {
+	Day    int
+	Year   int
+	Author string
+	Date   string
+}
+
+// newCmd scaffolds the command package and data files for a day
+var newCmd = &cobra.Command{
+	Use:   "new <day>",
+	Short: "Scaffold the command package and data files for a day",
+	Long: `Generates the cmd/dayN package with solver stubs and a sample test, empty
+scenario files, empty input and sample data files, and registers the command in
+cmd/root.go. Existing files are never overwritten, except that --force
+regenerates the package's Go sources, e.g. to replace a placeholder stub.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day, err := cmn.ParseDay(args[0])
+		if err != nil {
+			return err
+		}
+
+		srcDir, err := cmd.Flags().GetString("src")
+		if err != nil {
+			return err
+		}
+		author, err := cmd.Flags().GetString("author")
+		if err != nil {
+			return err
+		}
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		data := scaffoldData{
+			Day:    day,
+			Year:   cmn.EventYear,
+			Author: author,
+			Date:   time.Now().Format(time.DateOnly),
+		}
+		paths, err := ScaffoldDay(srcDir, data, force)
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return err
+	},
+}
+
+func init() {
+	newCmd.Flags().String("src", ".", "Root of the advent module source, containing go.mod")
+	newCmd.Flags().String("author", "Joseph Bochinski", "Author name for the file headers")
+	newCmd.Flags().Bool("force", false, "Overwrite the day's existing Go sources")
+}
+
+// ScaffoldDay generates the files for the day and registers its command,
+// returning the paths of the files that were created. Existing Go sources are
+// only overwritten if force is set, the placeholders never are
+func ScaffoldDay(srcDir string, data scaffoldData, force bool) (paths []string, err error) {
+	if _, err = os.Stat(filepath.Join(srcDir, "go.mod")); err != nil {
+		return nil, fmt.Errorf("%s is not the module root: %w", srcDir, err)
+	}
+
+	dayName := "day" + strconv.Itoa(data.Day)
+	pkgDir := filepath.Join(srcDir, "cmd", dayName)
+
+	sources := []struct{ fileName, templateName string }{
+		{dayName + ".go", "day.go.tmpl"},
+		{dayName + "_test.go", "day_test.go.tmpl"},
+	}
+	for _, src := range sources {
+		path := filepath.Join(pkgDir, src.fileName)
+		if _, err = os.Stat(path); err == nil && !force {
+			return nil, fmt.Errorf("%s already exists, refusing to overwrite it without --force", path)
+		}
+	}
+
+	if err = os.MkdirAll(pkgDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	for _, src := range sources {
+		source := &bytes.Buffer{}
+		if err = scaffoldTemplates.ExecuteTemplate(source, src.templateName, data); err != nil {
+			return paths, err
+		}
+		formatted, err := format.Source(source.Bytes())
+		if err != nil {
+			return paths, err
+		}
+
+		path := filepath.Join(pkgDir, src.fileName)
+		if force {
+			err = cmn.WriteFileAtomic(path, formatted)
+		} else {
+			err = createFile(path, formatted)
+		}
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	// Placeholders for the scenario descriptions and puzzle data
+	placeholders := []string{
+		filepath.Join(pkgDir, "scenario1.txt"),
+		filepath.Join(pkgDir, "scenario2.txt"),
+		filepath.Join(cmn.DayDir(data.Day), cmn.InputFileName),
+		filepath.Join(cmn.DayDir(data.Day), cmn.SampleFileName),
+	}
+	for _, path := range placeholders {
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return paths, err
+		}
+		if err = createFile(path, nil); errors.Is(err, fs.ErrExist) {
+			continue
+		} else if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	rootPath := filepath.Join(srcDir, "cmd", "root.go")
+	if err = registerDayCmd(rootPath, data.Day); err != nil {
+		return paths, err
+	}
+	return paths, nil
+}
+
+// createFile writes the data to a new file, failing if the file already exists
+func createFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// registerDayCmd adds the import and AddCommand call for the day's command to
+// root.go, if they aren't there already
+func registerDayCmd(rootPath string, day int) error {
+	source, err := os.ReadFile(rootPath)
+	if err != nil {
+		return err
+	}
+	text := string(source)
+
+	dayName := "day" + strconv.Itoa(day)
+	importLine := fmt.Sprintf("\t\"advent/cmd/%s\"\n", dayName)
+	addLine := fmt.Sprintf("\trootCmd.AddCommand(%s.Day%dCmd)\n", dayName, day)
+
+	if !strings.Contains(text, importLine) {
+		// gofmt sorts the import into place
+		text = strings.Replace(text, "import (\n", "import (\n"+importLine, 1)
+	}
+
+	if !strings.Contains(text, addLine) {
+		lastAdd := strings.LastIndex(text, "\trootCmd.AddCommand(day")
+		if lastAdd < 0 {
+			return fmt.Errorf("couldn't find where to register the command in %s", rootPath)
+		}
+		lineEnd := lastAdd + strings.Index(text[lastAdd:], "\n") + 1
+		text = text[:lineEnd] + addLine + text[lineEnd:]
+	}
+
+	formatted, err := format.Source([]byte(text))
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(formatted, source) {
+		return nil
+	}
+	return os.WriteFile(rootPath, formatted, 0o644)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyTree copies the files under src into dst
+func copyTree(t *testing.T, src, dst string) {
+	t.Helper()
+	err := filepath.WalkDir(src, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0o755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), data, 0o644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestScaffoldDayTests scaffolds a day into a copy of the module and runs the
+// generated tests against recorded sample answers for both parts
+func TestScaffoldDayTests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test on a copy of the module")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	srcDir := t.TempDir()
+	for _, name := range []string{"go.mod", "go.sum", filepath.Join("cmd", "root.go")} {
+		data, err := os.ReadFile(filepath.Join("..", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.MkdirAll(filepath.Dir(filepath.Join(srcDir, name)), 0o755)
+		if err = os.WriteFile(filepath.Join(srcDir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	copyTree(t, filepath.Join("..", "cmn"), filepath.Join(srcDir, "cmn"))
+
+	dataDir := cmn.DataDir
+	cmn.DataDir = filepath.Join(srcDir, "data")
+	defer func() { cmn.DataDir = dataDir }()
+
+	if _, err = ScaffoldDay(srcDir, scaffoldData{Day: 26, Year: 2024, Author: "Test", Date: "2024-12-26"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Have the part two stub report the recorded sample answer
+	dayPath := filepath.Join(srcDir, "cmd", "day26", "day26.go")
+	source, err := os.ReadFile(dayPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := "defer handler.StartProfile(\"SolvePuzzleTwo\")()\n"
+	if !strings.Contains(string(source), stub) {
+		t.Fatalf("couldn't find the part two stub in:\n%s", source)
+	}
+	source = []byte(strings.Replace(string(source), stub, stub+"\thandler.Report(\"Answer:\", 2)\n", 1))
+	if err = os.WriteFile(dayPath, source, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	answersPath := filepath.Join(cmn.DayDir(26), cmn.AnswersFile)
+	if err = os.WriteFile(answersPath, []byte("sample2: 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	goTest := exec.Command(goBin, "test", "-run", "TestSamples", "-v", "./cmd/day26")
+	goTest.Dir = srcDir
+	goTest.Env = append(os.Environ(), cmn.DataDirEnvVar+"="+cmn.DataDir)
+	output, err := goTest.CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated tests failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "--- PASS: TestSamples/puzzle2") {
+		t.Errorf("expected the part two sample to pass:\n%s", output)
+	}
+}
+
+// scaffoldSrcDir creates a module root with a copy of root.go to scaffold into,
+// and points the data dir inside it
+func scaffoldSrcDir(t *testing.T) string {
+	t.Helper()
+	srcDir := t.TempDir()
+	os.MkdirAll(filepath.Join(srcDir, "cmd"), 0o755)
+	if err := os.WriteFile(filepath.Join(srcDir, "go.mod"), []byte("module advent\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	root, err := os.ReadFile("root.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(srcDir, "cmd", "root.go"), root, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dataDir := cmn.DataDir
+	cmn.DataDir = filepath.Join(srcDir, "data")
+	t.Cleanup(func() { cmn.DataDir = dataDir })
+	return srcDir
+}
+
+func TestScaffoldDayOverwrite(t *testing.T) {
+	srcDir := scaffoldSrcDir(t)
+	data := scaffoldData{Day: 26, Year: 2024, Author: "Test", Date: "2024-12-26"}
+	dayPath := filepath.Join(srcDir, "cmd", "day26", "day26.go")
+	os.MkdirAll(filepath.Dir(dayPath), 0o755)
+	if err := os.WriteFile(dayPath, []byte("package day26\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if paths, err := ScaffoldDay(srcDir, data, false); err == nil || len(paths) != 0 {
+		t.Fatalf("expected a refusal to overwrite, got %v and %v", paths, err)
+	}
+	if source, _ := os.ReadFile(dayPath); string(source) != "package day26\n" {
+		t.Fatalf("expected the existing file to be left alone, got:\n%s", source)
+	}
+
+	paths, err := ScaffoldDay(srcDir, data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) < 2 || paths[0] != dayPath || paths[1] != filepath.Join(srcDir, "cmd", "day26", "day26_test.go") {
+		t.Errorf("expected the Go sources to be created first and in order, got %v", paths)
+	}
+	if source, _ := os.ReadFile(dayPath); !strings.Contains(string(source), "func SolvePuzzleOne(") {
+		t.Errorf("expected --force to regenerate the sources, got:\n%s", source)
+	}
+}
+
+func TestRegisterDayCmd(t *testing.T) {
+	rootPath := filepath.Join(scaffoldSrcDir(t), "cmd", "root.go")
+	for range 2 {
+		if err := registerDayCmd(rootPath, 26); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source, err := os.ReadFile(rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"\t\"advent/cmd/day26\"\n", "\trootCmd.AddCommand(day26.Day26Cmd)\n"} {
+		if count := strings.Count(string(source), line); count != 1 {
+			t.Errorf("expected %q once, found it %d times", line, count)
+		}
+	}
+	if _, err = format.Source(source); err != nil {
+		t.Errorf("expected root.go to stay valid Go: %v", err)
+	}
+}
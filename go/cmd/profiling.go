@@ -0,0 +1,161 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"errors"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/spf13/cobra"
+)
+
+// profiling holds the state of the profiles started from the root flags
+var profiling struct {
+	cpuFile   *os.File
+	traceFile *os.File
+	memPath   string
+	outPath   string
+	format    string
+}
+
+// addProfilingFlags adds the flags used to profile any command
+func addProfilingFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("cpuprofile", "", "Write a CPU pprof profile to the file")
+	cmd.PersistentFlags().String("memprofile", "", "Write a heap pprof profile to the file")
+	cmd.PersistentFlags().String("trace", "", "Write a runtime execution trace to the file")
+	cmd.PersistentFlags().String("profile-out", "", "Write a summary of the profiled spans to the file, - for stdout")
+	cmd.PersistentFlags().String("profile-format", "json", "Format of the profile summary: json or csv")
+	cmd.PersistentFlags().Bool("profile-allocs", false, "Measure the allocations of the profiled tasks, implied by --profile-out")
+}
+
+// startProfiling starts the CPU profile and trace requested by the flags, the
+// remaining profiles are written by stopProfiling. Allocations are only
+// measured if they're output
+func startProfiling(cmd *cobra.Command) (err error) {
+	flags := cmd.Flags()
+	if profiling.memPath, err = flags.GetString("memprofile"); err != nil {
+		return err
+	}
+	if profiling.outPath, err = flags.GetString("profile-out"); err != nil {
+		return err
+	}
+	if profiling.format, err = flags.GetString("profile-format"); err != nil {
+		return err
+	}
+	// Validated up front so a bad format doesn't waste the run
+	if err = cmn.ValidateProfileFormat(profiling.format); err != nil {
+		return err
+	}
+
+	profileAllocs, err := flags.GetBool("profile-allocs")
+	if err != nil {
+		return err
+	}
+	if profileAllocs || profiling.outPath != "" {
+		cmn.ProfileMemory = true
+	}
+
+	cpuPath, err := flags.GetString("cpuprofile")
+	if err != nil {
+		return err
+	}
+	if cpuPath != "" {
+		if profiling.cpuFile, err = os.Create(cpuPath); err != nil {
+			return err
+		}
+		if err = pprof.StartCPUProfile(profiling.cpuFile); err != nil {
+			return err
+		}
+	}
+
+	tracePath, err := flags.GetString("trace")
+	if err != nil {
+		return err
+	}
+	if tracePath != "" {
+		if profiling.traceFile, err = os.Create(tracePath); err != nil {
+			return err
+		}
+		if err = trace.Start(profiling.traceFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopProfiling stops the running profiles and writes the heap profile and
+// span summary
+func stopProfiling() error {
+	errs := []error{}
+
+	if profiling.cpuFile != nil {
+		pprof.StopCPUProfile()
+		errs = append(errs, profiling.cpuFile.Close())
+		profiling.cpuFile = nil
+	}
+
+	if profiling.traceFile != nil {
+		trace.Stop()
+		errs = append(errs, profiling.traceFile.Close())
+		profiling.traceFile = nil
+	}
+
+	if profiling.memPath != "" {
+		errs = append(errs, writeHeapProfile(profiling.memPath))
+	}
+
+	if profiling.outPath == "-" {
+		errs = append(errs, cmn.WriteProfileSummary(os.Stdout, profiling.format))
+	} else if profiling.outPath != "" {
+		errs = append(errs, writeProfileSummary(profiling.outPath, profiling.format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeHeapProfile writes the heap profile to the file at the path
+func writeHeapProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Collect garbage so the profile reflects the live heap
+	runtime.GC()
+	return pprof.WriteHeapProfile(file)
+}
+
+// writeProfileSummary writes the span summary to the file at the path
+func writeProfileSummary(path, format string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return cmn.WriteProfileSummary(file, format)
+}
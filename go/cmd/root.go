@@ -47,7 +47,17 @@ import (
 	"advent/cmd/day7"
 	"advent/cmd/day8"
 	"advent/cmd/day9"
+	"advent/cmn"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
@@ -62,17 +72,191 @@ examples and usage of using your application. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	// Run: func(cmd *cobra.Command, args []string) { },
+	// Errors are printed by Execute, which also picks the exit code
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		dataDir, err := cmd.Flags().GetString("data-dir")
+		if err != nil {
+			return err
+		}
+		cmn.DataDir = dataDir
+
+		if err = configureLogging(cmd); err != nil {
+			return err
+		}
+
+		if err = configureFormat(cmd); err != nil {
+			return err
+		}
+
+		if err = startProfiling(cmd); err != nil {
+			return err
+		}
+
+		if err = applyTimeout(cmd); err != nil {
+			return err
+		}
+
+		return validatePuzzleFlag(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			PrintSolverList(os.Stdout)
+			return nil
+		}
+		return cmd.Help()
+	},
+}
+
+// configureLogging sets the default logger from the log flags, logs are always
+// written to stderr so that the answers on stdout stay clean
+func configureLogging(cmd *cobra.Command) error {
+	levelName, err := cmd.Flags().GetString("log-level")
+	if err != nil {
+		return err
+	}
+	level, err := cmn.ParseLogLevel(levelName)
+	if err != nil {
+		return err
+	}
+	if debug, _ := cmd.Flags().GetBool("debug"); debug && !cmd.Flags().Changed("log-level") {
+		level = slog.LevelDebug
+	}
+
+	format, err := cmd.Flags().GetString("log-format")
+	if err != nil {
+		return err
+	}
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid log format %q, expected text or json", format)
+	}
+
+	components, err := cmd.Flags().GetStringSlice("log-filter")
+	if err != nil {
+		return err
+	}
+
+	// Progress lines would corrupt output meant for other programs
+	if format == "json" {
+		cmn.ProgressEnabled = false
+	}
+
+	slog.SetDefault(cmn.NewLogger(os.Stderr, cmn.LogConfig{
+		Level:      level,
+		JSON:       format == "json",
+		Components: components,
+	}))
+	return nil
+}
+
+// configureFormat validates the format flag. The json and csv formats are meant
+// for other programs, so the profiling output moves to stderr and the progress
+// lines are disabled to keep stdout clean
+func configureFormat(cmd *cobra.Command) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	if err = cmn.ValidateFormat(format); err != nil {
+		return err
+	}
+
+	if format != cmn.FormatText {
+		cmn.ProfileOut = os.Stderr
+		cmn.ProgressEnabled = false
+		// The records include the allocations
+		cmn.ProfileMemory = true
+	}
+	return nil
+}
+
+// validatePuzzleFlag checks the puzzle-num flag before any command runs. For the
+// daily commands the puzzle must also have a registered solver
+func validatePuzzleFlag(cmd *cobra.Command, args []string) error {
+	puzzleNum, err := cmd.Flags().GetInt("puzzle-num")
+	if err != nil {
+		return err
+	}
+	if puzzleNum < 1 {
+		return fmt.Errorf("invalid puzzle number %d, puzzle numbers start at 1", puzzleNum)
+	}
+
+	// Only the daily commands have the day-num flag
+	if cmd.Flags().Lookup("day-num") == nil {
+		return nil
+	}
+	day, err := cmn.CmdDay(cmd)
+	if err != nil {
+		return err
+	}
+	if !cmn.HasSolver(day, puzzleNum) {
+		return &cmn.SolverUndefinedError{DayNum: day, PuzzleNum: puzzleNum, Hint: "see `advent --list`"}
+	}
+	return nil
+}
+
+// cancelTimeout releases the timeout context set up by applyTimeout
+var cancelTimeout context.CancelFunc
+
+// applyTimeout limits the command's context to the --timeout duration
+func applyTimeout(cmd *cobra.Command) error {
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil || timeout <= 0 {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+	cmd.SetContext(ctx)
+	return nil
+}
+
+// PrintSolverList prints each registered day with the puzzles it has solvers for
+func PrintSolverList(out io.Writer) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DAY\tCOMMAND\tPUZZLES")
+	for _, day := range cmn.RegisteredDays() {
+		puzzles := []string{}
+		for _, puzzleNum := range day.PuzzleNums() {
+			puzzles = append(puzzles, strconv.Itoa(puzzleNum))
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\n", day.DayNum, day.Cmd.Name(), strings.Join(puzzles, ", "))
+	}
+	writer.Flush()
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are printed to stderr and mapped to the exit codes defined by
+// cmn.ExitCode. Commands must return their errors rather than exiting, so
+// deferred cleanup and profiling always finish.
 func Execute() {
-	err := rootCmd.Execute()
+	// The first Ctrl-C cancels the run, restoring the default handling so a
+	// second one kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if cancelTimeout != nil {
+		cancelTimeout()
+	}
+	if stopErr := stopProfiling(); stopErr != nil {
+		err = errors.Join(err, stopErr)
+	}
 	if err != nil {
-		os.Exit(1)
+		message := strings.TrimRight(err.Error(), "\n")
+		if !strings.HasPrefix(message, "ERROR") {
+			message = "Error: " + message
+		}
+		fmt.Fprintln(os.Stderr, message)
+		os.Exit(cmn.ExitCode(err))
 	}
 }
 
@@ -86,9 +270,18 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.Flags().BoolP("list", "l", false, "List the puzzles each day has solvers for")
 	rootCmd.PersistentFlags().IntP("puzzle-num", "p", 1, "The puzzle number to run")
 	rootCmd.PersistentFlags().BoolP("sample", "s", false, "Run the sample data")
-	rootCmd.PersistentFlags().BoolP("debug", "D", false, "Enable debug output")
+	rootCmd.PersistentFlags().BoolP("debug", "D", false, "Enable debug output, shorthand for --log-level debug")
+	rootCmd.PersistentFlags().String("log-level", "info", "Minimum log level: trace, debug, info, warn or error")
+	rootCmd.PersistentFlags().String("log-format", "text", "Log output format: text or json")
+	rootCmd.PersistentFlags().String("format", cmn.FormatText, "Output format of the results: text, json or csv")
+	rootCmd.PersistentFlags().StringSlice("log-filter", nil, "Limit debug and trace logs to these components, e.g. day2,day5.checker")
+	addProfilingFlags(rootCmd)
+	rootCmd.PersistentFlags().String("data-dir", cmn.DataDir, "Directory containing the puzzle data, defaults to $"+cmn.DataDirEnvVar+" if set")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Cancel the run after this long, e.g. 30s, 0 for no limit")
+	rootCmd.PersistentFlags().StringP("input", "f", "", "Path to the puzzle data, - to read from stdin, may be gzip compressed")
 	rootCmd.AddCommand(day1.LocationCheck)
 	rootCmd.AddCommand(day2.SafeReports)
 	rootCmd.AddCommand(day3.MullItCmd)
@@ -114,5 +307,11 @@ func init() {
 	rootCmd.AddCommand(day23.Day23Cmd)
 	rootCmd.AddCommand(day24.Day24Cmd)
 	rootCmd.AddCommand(day25.Day25Cmd)
+	rootCmd.AddCommand(allCmd)
+	rootCmd.AddCommand(fetchCmd)
+	rootCmd.AddCommand(submitCmd)
+	rootCmd.AddCommand(samplesCmd)
+	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(benchCmd)
 
 }
@@ -0,0 +1,66 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestValidatePuzzleFlag(t *testing.T) {
+	tests := []struct {
+		args      []string
+		daily     bool
+		undefined bool
+		invalid   bool
+	}{
+		{[]string{"-p", "1"}, true, false, false},
+		{[]string{"-p", "2"}, true, false, false},
+		{[]string{"-p", "0"}, true, false, true},
+		{[]string{"-p", "3"}, true, true, false},
+		{[]string{"-p", "0"}, false, false, true},
+		{[]string{"-p", "3"}, false, false, false},
+	}
+	for _, test := range tests {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().IntP("puzzle-num", "p", 1, "")
+		if test.daily {
+			cmd.Flags().Int("day-num", 1, "")
+		}
+		if err := cmd.ParseFlags(test.args); err != nil {
+			t.Fatal(err)
+		}
+
+		err := validatePuzzleFlag(cmd, nil)
+		var undefined *cmn.SolverUndefinedError
+		if isUndefined := errors.As(err, &undefined); isUndefined != test.undefined {
+			t.Errorf("%v (daily: %v): expected undefined = %v, got %v", test.args, test.daily, test.undefined, err)
+		}
+		if isInvalid := err != nil && strings.Contains(err.Error(), "invalid puzzle number"); isInvalid != test.invalid {
+			t.Errorf("%v (daily: %v): expected invalid = %v, got %v", test.args, test.daily, test.invalid, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// samplesCmd extracts the sample data from a saved puzzle description page
+var samplesCmd = &cobra.Command{
+	Use:   "samples <day> [html file]",
+	Short: "Extract the sample data from a saved puzzle description",
+	Long: `Parses a locally saved puzzle description page, defaulting to the day's
+` + cmn.PuzzleHTMLFile + ` in the data directory, and writes the example blocks to the
+day's sample files. The emphasized expected answers are added to the day's
+` + cmn.AnswersFile + ` file.`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day, err := cmn.ParseDay(args[0])
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(cmn.DayDir(day), cmn.PuzzleHTMLFile)
+		if len(args) > 1 {
+			path = args[1]
+		}
+
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		page, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		samples, err := cmn.ExtractSamples(string(page))
+		if err != nil {
+			return err
+		}
+
+		paths, err := cmn.WriteSamples(day, samples, force)
+		for _, path := range paths {
+			fmt.Println("Wrote", path)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, sample := range samples {
+			answer := sample.Answer
+			if answer == "" {
+				answer = "not found"
+			}
+			fmt.Printf("Part %d sample answer: %s\n", sample.PuzzleNum, answer)
+		}
+		return nil
+	},
+}
+
+func init() {
+	samplesCmd.Flags().Bool("force", false, "Replace sample files that already have content and their recorded answers")
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2024 Joseph Bochinski <jmbochinski@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"advent/cmn"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// submitCmd submits the answer to a puzzle and records it in the ledger
+var submitCmd = &cobra.Command{
+	Use:   "submit <day> <part>",
+	Short: "Solve a puzzle and submit the answer",
+	Long: `Runs the solver for the day and part against the puzzle input and submits the
+answer. Every attempt is recorded in the ` + cmn.LedgerFile + ` ledger in the data
+directory, and answers the ledger shows can't be right (already wrong, or
+outside of previous too high/too low answers) are rejected before submitting.
+Correct answers are saved to the day's ` + cmn.AnswersFile + ` file.
+
+Uses the same session token and base URL settings as the fetch command.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day, err := cmn.ParseDay(args[0])
+		if err != nil {
+			return err
+		}
+		part, err := cmn.ParsePart(args[1])
+		if err != nil {
+			return err
+		}
+
+		answer, err := cmd.Flags().GetString("answer")
+		if err != nil {
+			return err
+		}
+		if answer == "" {
+			if answer, err = solveForSubmit(cmd.Context(), day, part); err != nil {
+				return err
+			}
+		}
+
+		ledger, err := cmn.LoadLedger()
+		if err != nil {
+			return err
+		}
+		if err = ledger.Check(day, part, answer); err != nil {
+			return err
+		}
+
+		client, err := newClient(cmd)
+		if err != nil {
+			return err
+		}
+		submission, err := client.SubmitAnswer(day, part, answer)
+		if err != nil {
+			return err
+		}
+		if err = ledger.Record(submission); err != nil {
+			return err
+		}
+
+		fmt.Println(submission.Message)
+
+		switch submission.Outcome {
+		case cmn.OutcomeCorrect:
+			return cmn.SaveAnswer(day, part, false, answer)
+		case cmn.OutcomeWait:
+			return fmt.Errorf("submitted too recently, wait %v before trying again", submission.Wait)
+		}
+		return fmt.Errorf("answer %s was not accepted: %s", answer, submission.Outcome)
+	},
+}
+
+func init() {
+	addClientFlags(submitCmd)
+	submitCmd.Flags().StringP("answer", "a", "", "Submit this answer instead of running the solver")
+}
+
+// solveForSubmit runs the solver for the puzzle and returns the reported answer
+func solveForSubmit(ctx context.Context, day, part int) (string, error) {
+	if !cmn.HasSolver(day, part) {
+		return "", &cmn.SolverUndefinedError{DayNum: day, PuzzleNum: part}
+	}
+
+	handler, err := cmn.NewHandlerE(nil, cmn.WithPuzzle(day, part, false), cmn.WithContext(ctx), cmn.WithOutput(os.Stdout))
+	defer handler.Close()
+	if err != nil {
+		return "", err
+	}
+
+	if err = handler.Solve(); err != nil {
+		return "", err
+	}
+	if handler.Answer == "" {
+		return "", errors.New("the solver did not report an answer")
+	}
+	return handler.Answer, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: answers
+	Description: Code for loading the known answers to the puzzles
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// AnswersFile is the name of the file in each day's data directory that holds
+// the known answers to the puzzles
+const AnswersFile = "answers.txt"
+
+// Answers maps an answer key (see AnswerKey) to the known answer
+type Answers map[string]string
+
+// AnswerKey builds the key used in the answers file for a puzzle, e.g.
+// "sample1" or "puzzle2"
+func AnswerKey(puzzleNum int, isSample bool) string {
+	if isSample {
+		return "sample" + strconv.Itoa(puzzleNum)
+	}
+	return "puzzle" + strconv.Itoa(puzzleNum)
+}
+
+// Get returns the known answer for the puzzle, if there is one
+func (a Answers) Get(puzzleNum int, isSample bool) (answer string, ok bool) {
+	answer, ok = a[AnswerKey(puzzleNum, isSample)]
+	return answer, ok
+}
+
+// LoadAnswers reads the known answers for the given day. Each line of the file
+// is formatted as `key: answer`, blank lines and lines starting with # are
+// ignored. A missing file is not an error and results in no known answers
+func LoadAnswers(day int) (answers Answers, err error) {
+	answers = Answers{}
+	path := filepath.Join(DayDir(day), AnswersFile)
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return answers, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, answer, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, &InvalidDataError{Line: line, Err: errors.New("expected `key: answer`")}
+		}
+		answers[strings.TrimSpace(key)] = strings.TrimSpace(answer)
+	}
+
+	return answers, scanner.Err()
+}
+
+// SaveAnswer adds the answer for the puzzle to the day's answers file, unless
+// the file already has an answer for it
+func SaveAnswer(day, puzzleNum int, isSample bool, answer string) error {
+	return writeAnswer(day, puzzleNum, isSample, answer, false)
+}
+
+// ReplaceAnswer sets the answer for the puzzle in the day's answers file,
+// replacing any answer the file already has for it
+func ReplaceAnswer(day, puzzleNum int, isSample bool, answer string) error {
+	return writeAnswer(day, puzzleNum, isSample, answer, true)
+}
+
+// writeAnswer adds the answer for the puzzle to the day's answers file. An
+// existing answer is replaced in place if replace is set, otherwise it's kept
+func writeAnswer(day, puzzleNum int, isSample bool, answer string, replace bool) error {
+	answers, err := LoadAnswers(day)
+	if err != nil {
+		return err
+	}
+	key := AnswerKey(puzzleNum, isSample)
+	entry := key + ": " + answer
+	if existing, exists := answers[key]; exists && (!replace || existing == answer) {
+		return nil
+	}
+
+	path := filepath.Join(DayDir(day), AnswersFile)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if _, exists := answers[key]; exists {
+		lines := strings.SplitAfter(string(data), "\n")
+		for i, line := range lines {
+			if lineKey, _, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(lineKey) == key {
+				lines[i] = entry + "\n"
+			}
+		}
+		return WriteFileAtomic(path, []byte(strings.Join(lines, "")))
+	}
+
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	data = append(data, []byte(entry+"\n")...)
+
+	return WriteFileAtomic(path, data)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: bench
+	Description: Code for parsing and comparing Go benchmark results
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BenchSamples holds the measurements of a benchmark from repeated runs
+type BenchSamples struct {
+	Name        string    // Name is the package qualified benchmark name, e.g. advent/cmd/day1.BenchmarkParseP1Data
+	NsPerOp     []float64 // NsPerOp is the time per operation of each run
+	BytesPerOp  []float64 // BytesPerOp is the bytes allocated per operation of each run
+	AllocsPerOp []float64 // AllocsPerOp is the allocations per operation of each run
+}
+
+// BenchResults maps the package qualified benchmark names to their samples
+type BenchResults map[string]*BenchSamples
+
+// BenchComparison is the change in a benchmark's time per operation between a
+// baseline and the current results
+type BenchComparison struct {
+	Name        string  // Name is the package qualified benchmark name
+	Old         float64 // Old is the median ns/op of the baseline
+	New         float64 // New is the median ns/op of the current results
+	Delta       float64 // Delta is the relative change of the medians, e.g. 0.1 for 10% slower
+	P           float64 // P is the p-value of the Mann-Whitney U test of the samples
+	Significant bool    // Significant is set if P is below the significance level
+	Regression  bool    // Regression is set if the change is significant and slower than the threshold
+}
+
+// ParseBenchOutput parses the output of `go test -bench`, collecting the
+// samples of each benchmark across repeated runs (-count)
+func ParseBenchOutput(r io.Reader) (BenchResults, error) {
+	results := BenchResults{}
+	pkg := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(value)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+
+		// Strip the GOMAXPROCS suffix, e.g. BenchmarkX-8
+		name := fields[0]
+		if idx := strings.LastIndex(name, "-"); idx > 0 {
+			if _, err := strconv.Atoi(name[idx+1:]); err == nil {
+				name = name[:idx]
+			}
+		}
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+
+		samples, exists := results[name]
+		if !exists {
+			samples = &BenchSamples{Name: name}
+			results[name] = samples
+		}
+
+		// Values are followed by their units, after the iteration count
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				samples.NsPerOp = append(samples.NsPerOp, value)
+			case "B/op":
+				samples.BytesPerOp = append(samples.BytesPerOp, value)
+			case "allocs/op":
+				samples.AllocsPerOp = append(samples.AllocsPerOp, value)
+			}
+		}
+	}
+	return results, scanner.Err()
+}
+
+// CompareBench compares the ns/op of the benchmarks found in both results. A
+// change is significant if the Mann-Whitney U test p-value is below alpha, and
+// a regression if it's also slower by more than the threshold (e.g. 0.05 for 5%)
+func CompareBench(baseline, current BenchResults, alpha, threshold float64) []*BenchComparison {
+	comparisons := []*BenchComparison{}
+	for name, samples := range current {
+		old, exists := baseline[name]
+		if !exists || len(old.NsPerOp) == 0 || len(samples.NsPerOp) == 0 {
+			continue
+		}
+
+		comparison := &BenchComparison{
+			Name: name,
+			Old:  Median(old.NsPerOp),
+			New:  Median(samples.NsPerOp),
+			P:    MannWhitneyU(old.NsPerOp, samples.NsPerOp),
+		}
+		if comparison.Old > 0 {
+			comparison.Delta = (comparison.New - comparison.Old) / comparison.Old
+		}
+		comparison.Significant = comparison.P < alpha
+		comparison.Regression = comparison.Significant && comparison.Delta > threshold
+
+		comparisons = append(comparisons, comparison)
+	}
+
+	sort.Slice(comparisons, func(i, j int) bool {
+		return comparisons[i].Name < comparisons[j].Name
+	})
+	return comparisons
+}
+
+// Median returns the median of the values
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// MannWhitneyU returns the two-sided p-value of the Mann-Whitney U test that the
+// samples come from the same distribution, using the normal approximation with
+// a tie correction
+func MannWhitneyU(a, b []float64) float64 {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type ranked struct {
+		value float64
+		fromA bool
+	}
+	all := make([]ranked, 0, len(a)+len(b))
+	for _, value := range a {
+		all = append(all, ranked{value, true})
+	}
+	for _, value := range b {
+		all = append(all, ranked{value, false})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].value < all[j].value
+	})
+
+	// Tied values share the average of their ranks
+	rankSumA, tieSum := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromA {
+				rankSumA += rank
+			}
+		}
+		ties := float64(j - i)
+		tieSum += ties*ties*ties - ties
+		i = j
+	}
+
+	n := n1 + n2
+	u := rankSumA - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieSum/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+
+	// Continuity correction
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: bench_test
+	Description: Tests for parsing and comparing benchmark results
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"strings"
+	"testing"
+)
+
+const benchOutput = `goos: linux
+goarch: amd64
+pkg: advent/cmd/day1
+BenchmarkParseP1Data-8   	    1000	      1000 ns/op	     512 B/op	       4 allocs/op
+BenchmarkParseP1Data-8   	    1000	      1010 ns/op	     512 B/op	       4 allocs/op
+PASS
+pkg: advent/cmd/day2
+BenchmarkSolvePuzzleOne-8	     100	     20000 ns/op
+PASS
+`
+
+func TestParseBenchOutput(t *testing.T) {
+	results, err := ParseBenchOutput(strings.NewReader(benchOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parse, exists := results["advent/cmd/day1.BenchmarkParseP1Data"]
+	if !exists {
+		t.Fatalf("day1 benchmark not parsed: %v", results)
+	}
+	if len(parse.NsPerOp) != 2 || parse.NsPerOp[1] != 1010 {
+		t.Errorf("unexpected ns/op samples: %v", parse.NsPerOp)
+	}
+	if len(parse.AllocsPerOp) != 2 || parse.AllocsPerOp[0] != 4 {
+		t.Errorf("unexpected allocs/op samples: %v", parse.AllocsPerOp)
+	}
+	if _, exists := results["advent/cmd/day2.BenchmarkSolvePuzzleOne"]; !exists {
+		t.Errorf("day2 benchmark not parsed: %v", results)
+	}
+}
+
+func TestCompareBench(t *testing.T) {
+	samples := func(values ...float64) BenchResults {
+		return BenchResults{"b": &BenchSamples{Name: "b", NsPerOp: values}}
+	}
+	baseline := samples(100, 101, 99, 100, 102, 98, 100, 101, 99, 100)
+
+	slower := CompareBench(baseline, samples(150, 151, 149, 150, 152, 148, 150, 151, 149, 150), 0.05, 0.05)
+	if len(slower) != 1 || !slower[0].Regression {
+		t.Errorf("expected a regression, got %+v", slower[0])
+	}
+
+	same := CompareBench(baseline, samples(101, 99, 100, 102, 98, 100, 100, 101, 99, 100), 0.05, 0.05)
+	if len(same) != 1 || same[0].Significant {
+		t.Errorf("expected no significant change, got %+v", same[0])
+	}
+
+	faster := CompareBench(baseline, samples(50, 51, 49, 50, 52, 48, 50, 51, 49, 50), 0.05, 0.05)
+	if len(faster) != 1 || !faster[0].Significant || faster[0].Regression {
+		t.Errorf("expected a significant improvement, got %+v", faster[0])
+	}
+}
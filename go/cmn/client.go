@@ -0,0 +1,261 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: client
+	Description: HTTP client for downloading puzzle data from the Advent of Code site
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EventYear         = 2024                       // EventYear is the year of the Advent of Code event
+	DefaultBaseURL    = "https://adventofcode.com" // DefaultBaseURL is the Advent of Code site
+	DefaultRateLimit  = 3 * time.Second            // DefaultRateLimit is the minimum time between requests
+	BaseURLEnvVar     = "AOC_BASE_URL"             // BaseURLEnvVar is the env variable that overrides the base URL
+	SessionEnvVar     = "AOC_SESSION"              // SessionEnvVar is the env variable holding the session token
+	SessionConfigFile = "advent/session"           // SessionConfigFile is the session token file in the user config dir
+	userAgent         = "github.com/jmbski/advent2024 by jmbochinski@gmail.com"
+)
+
+// Client is used to make requests to the Advent of Code site, requests are
+// throttled so that at most one request is made per RateLimit
+type Client struct {
+	BaseURL    string        // BaseURL is the root URL of the site, e.g. https://adventofcode.com
+	Session    string        // Session is the value of the session cookie used to authenticate
+	Year       int           // Year is the year of the event
+	RateLimit  time.Duration // RateLimit is the minimum time between requests
+	HTTPClient *http.Client  // HTTPClient is the client used to make the requests
+
+	mu          sync.Mutex // mu guards lastRequest
+	lastRequest time.Time  // lastRequest is the time the last request was made
+}
+
+// ClientOption is a functional option type for Client
+type ClientOption func(*Client)
+
+// WithBaseURL is a functional option that assigns the base URL of the client,
+// empty values are ignored
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.BaseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithSession is a functional option that assigns the session token of the
+// client, empty values are ignored
+func WithSession(session string) ClientOption {
+	return func(c *Client) {
+		if session != "" {
+			c.Session = session
+		}
+	}
+}
+
+// WithRateLimit is a functional option that assigns the minimum time between
+// requests
+func WithRateLimit(rateLimit time.Duration) ClientOption {
+	return func(c *Client) {
+		c.RateLimit = rateLimit
+	}
+}
+
+// NewClient creates a new Client. The base URL and session token default to the
+// values of the AOC_BASE_URL and AOC_SESSION env variables, the session token
+// falls back to the advent/session file in the user's config directory
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		BaseURL:    strings.TrimRight(GetEnvD(BaseURLEnvVar, DefaultBaseURL), "/"),
+		Session:    LoadSession(),
+		Year:       EventYear,
+		RateLimit:  DefaultRateLimit,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// LoadSession retrieves the session token from the AOC_SESSION env variable or
+// the session config file, returns "" if neither is set
+func LoadSession() string {
+	if session := os.Getenv(SessionEnvVar); session != "" {
+		return strings.TrimSpace(session)
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(configDir, SessionConfigFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// DayURL returns the URL of the given day's puzzle page
+func (c *Client) DayURL(day int) string {
+	return fmt.Sprintf("%s/%d/day/%d", c.BaseURL, c.Year, day)
+}
+
+// FetchInput downloads the puzzle input for the day
+func (c *Client) FetchInput(day int) ([]byte, error) {
+	resp, err := c.Do(http.MethodGet, c.DayURL(day)+"/input", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPStatusError{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
+
+// DownloadInput fetches the puzzle input for the day and saves it to the day's
+// input.txt. Inputs that have already been downloaded are never fetched again,
+// in which case fetched is false
+func (c *Client) DownloadInput(day int) (path string, fetched bool, err error) {
+	path = filepath.Join(DayDir(day), InputFileName)
+
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return path, false, nil
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return path, false, err
+	}
+
+	data, err := c.FetchInput(day)
+	if err != nil {
+		return path, false, err
+	}
+
+	if err = WriteFileAtomic(path, data); err != nil {
+		return path, false, err
+	}
+	return path, true, nil
+}
+
+// Do makes a request to the site, waiting for the rate limit and adding the
+// session cookie and user agent
+func (c *Client) Do(method, url string, body io.Reader, contentType string) (*http.Response, error) {
+	if c.Session == "" {
+		return nil, &SessionUndefinedError{}
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	req.Header.Set("User-Agent", userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	c.wait()
+	return c.HTTPClient.Do(req)
+}
+
+// wait blocks until the rate limit allows another request to be made
+func (c *Client) wait() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lastRequest.IsZero() {
+		if remaining := c.RateLimit - time.Since(c.lastRequest); remaining > 0 {
+			time.Sleep(remaining)
+		}
+	}
+	c.lastRequest = time.Now()
+}
+
+// WriteFileAtomic writes the data to a temporary file and then renames it to the
+// path, so that partial writes never leave a truncated file behind. The parent
+// directories are created if needed
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ParseDay parses and validates a day number CLI arg
+func ParseDay(arg string) (int, error) {
+	day, err := strconv.Atoi(arg)
+	if err != nil || day < 1 || day > 25 {
+		return 0, fmt.Errorf("invalid day %q, expected a number from 1 to 25", arg)
+	}
+	return day, nil
+}
+
+// ParsePart parses and validates a puzzle part CLI arg
+func ParsePart(arg string) (int, error) {
+	part, err := strconv.Atoi(arg)
+	if err != nil || part < 1 || part > 2 {
+		return 0, fmt.Errorf("invalid part %q, expected 1 or 2", arg)
+	}
+	return part, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: client_test
+	Description: Tests for downloading puzzle data against a local stand-in
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// newStandIn starts a local stand-in for the Advent of Code site that serves
+// day 1's input and responds to answer submissions with the given message
+func newStandIn(t *testing.T, message string, requests *int) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /2024/day/1/input", func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "token" {
+			http.Error(w, "missing session", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "3   4\n4   3\n")
+	})
+	mux.HandleFunc("POST /2024/day/1/answer", func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.FormValue("level") != "1" || r.FormValue("answer") == "" {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "<html><main><article><p>%s</p></article></main></html>", message)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// useTempDataDir points the DataDir at a temporary directory for the test
+func useTempDataDir(t *testing.T) {
+	t.Helper()
+	dataDir := DataDir
+	DataDir = t.TempDir()
+	t.Cleanup(func() { DataDir = dataDir })
+}
+
+func TestDownloadInputIsCached(t *testing.T) {
+	useTempDataDir(t)
+	requests := 0
+	server := newStandIn(t, "", &requests)
+	client := NewClient(WithBaseURL(server.URL), WithSession("token"), WithRateLimit(0))
+
+	path, fetched, err := client.DownloadInput(1)
+	if err != nil || !fetched {
+		t.Fatalf("first download: fetched = %v, err = %v", fetched, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "3   4\n4   3\n" {
+		t.Errorf("saved input = %q", data)
+	}
+
+	if _, fetched, err = client.DownloadInput(1); err != nil || fetched {
+		t.Fatalf("second download: fetched = %v, err = %v", fetched, err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestClientRequiresSession(t *testing.T) {
+	t.Setenv(SessionEnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	client := NewClient(WithBaseURL("http://127.0.0.1:0"))
+
+	if _, err := client.FetchInput(1); err == nil {
+		t.Fatal("expected an error without a session token")
+	} else if _, ok := err.(*SessionUndefinedError); !ok {
+		t.Errorf("err = %T, want *SessionUndefinedError", err)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	useTempDataDir(t)
+	requests := 0
+	server := newStandIn(t, "", &requests)
+	client := NewClient(WithBaseURL(server.URL), WithSession("token"), WithRateLimit(50*time.Millisecond))
+
+	start := time.Now()
+	for range 3 {
+		if _, err := client.FetchInput(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 requests took %v, want at least 100ms", elapsed)
+	}
+}
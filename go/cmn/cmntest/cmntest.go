@@ -0,0 +1,100 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmntest
+	Title: cmntest
+	Description: Helpers for testing and benchmarking the daily solvers
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmntest
+
+import (
+	"advent/cmn"
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+// Quiet discards the solver, profiling and progress output for the rest of the
+// test
+func Quiet(tb testing.TB) {
+	tb.Helper()
+	profileOut, recording, progress := cmn.ProfileOut, cmn.ProfileRecording, cmn.ProgressEnabled
+	cmn.ProfileOut, cmn.ProfileRecording, cmn.ProgressEnabled = io.Discard, false, false
+	tb.Cleanup(func() {
+		cmn.ProfileOut, cmn.ProfileRecording, cmn.ProgressEnabled = profileOut, recording, progress
+	})
+}
+
+// Input reads the data for the day and puzzle into memory, the test is skipped
+// if the data is missing or empty, or fails if cmn.RequireDataEnvVar is set
+func Input(tb testing.TB, day, puzzleNum int, isSample bool) []byte {
+	tb.Helper()
+	data, err := os.ReadFile(cmn.ResolveInputPath(day, puzzleNum, isSample))
+	if (err != nil || len(data) == 0) && os.Getenv(cmn.RequireDataEnvVar) != "" {
+		tb.Fatalf("no data for day %d puzzle %d (sample: %v): %v", day, puzzleNum, isSample, err)
+	}
+	if err != nil || len(data) == 0 {
+		tb.Skipf("no data for day %d puzzle %d (sample: %v)", day, puzzleNum, isSample)
+	}
+	return data
+}
+
+// Handler creates a handler for the day and puzzle that reads the in-memory
+// data and discards its output
+func Handler(tb testing.TB, day, puzzleNum int, data []byte, solvers ...cmn.HandlerFunc) *cmn.AdventHandler {
+	tb.Helper()
+	opts := []cmn.HandlerOption{
+		cmn.WithPuzzle(day, puzzleNum, false),
+		cmn.WithReader(bytes.NewReader(data)),
+		cmn.WithOutput(io.Discard),
+	}
+	if len(solvers) > 0 {
+		opts = append(opts, cmn.WithSolvers(solvers...))
+	}
+
+	handler, err := cmn.NewHandlerE(nil, opts...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return handler
+}
+
+// Benchmark runs the stage (a solver, or just its parsing) with a fresh handler
+// over the day's in-memory puzzle data on each iteration
+func Benchmark(b *testing.B, day, puzzleNum int, stage cmn.HandlerFunc) {
+	Quiet(b)
+	data := Input(b, day, puzzleNum, false)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		if err := stage(Handler(b, day, puzzleNum, data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: counter
+	Description: Multiset for counting occurrences of values
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Counter is a multiset counting the occurrences of each value, values with no
+// occurrences are never stored. It isn't safe for concurrent use.
+type Counter[T comparable] map[T]int
+
+// CounterEntry is a value and its count
+type CounterEntry[T comparable] struct {
+	Value T
+	Count int
+}
+
+// NewCounter creates a counter of the items
+func NewCounter[T comparable](items ...T) Counter[T] {
+	counter := Counter[T]{}
+	counter.Add(items...)
+	return counter
+}
+
+// CounterOf creates a counter of the values in the sequence
+func CounterOf[T comparable](seq iter.Seq[T]) Counter[T] {
+	counter := Counter[T]{}
+	for item := range seq {
+		counter.AddN(item, 1)
+	}
+	return counter
+}
+
+// Add counts a single occurrence of each item
+func (c Counter[T]) Add(items ...T) {
+	for _, item := range items {
+		c[item]++
+	}
+}
+
+// AddN adds n to the count of the item, which may be negative. The item is
+// removed if its count drops to zero or below.
+func (c Counter[T]) AddN(item T, n int) {
+	if count := c[item] + n; count > 0 {
+		c[item] = count
+	} else {
+		delete(c, item)
+	}
+}
+
+// Count returns the number of occurrences of the item
+func (c Counter[T]) Count(item T) int {
+	return c[item]
+}
+
+// Remove deletes all occurrences of the item
+func (c Counter[T]) Remove(item T) {
+	delete(c, item)
+}
+
+// Len returns the number of distinct values
+func (c Counter[T]) Len() int {
+	return len(c)
+}
+
+// Total returns the sum of all counts
+func (c Counter[T]) Total() int {
+	total := 0
+	for _, count := range c {
+		total += count
+	}
+	return total
+}
+
+// All iterates over the values and their counts in no particular order
+func (c Counter[T]) All() iter.Seq2[T, int] {
+	return maps.All(c)
+}
+
+// Clone returns a copy of the counter
+func (c Counter[T]) Clone() Counter[T] {
+	return maps.Clone(c)
+}
+
+// Merge adds the other counter's counts to the counter
+func (c Counter[T]) Merge(other Counter[T]) {
+	for item, count := range other {
+		c[item] += count
+	}
+}
+
+// Subtract removes the other counter's counts from the counter, dropping any
+// values whose count reaches zero
+func (c Counter[T]) Subtract(other Counter[T]) {
+	for item, count := range other {
+		c.AddN(item, -count)
+	}
+}
+
+// MostCommon returns the n values with the highest counts, highest first, or
+// every value if n is negative. Ties are in no particular order, use
+// MostCommonSorted if that matters.
+func (c Counter[T]) MostCommon(n int) []CounterEntry[T] {
+	return mostCommon(c.All(), n)
+}
+
+// SortedCounts iterates over a counter of ordered values in ascending order
+func SortedCounts[T cmp.Ordered](c Counter[T]) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for _, item := range slices.Sorted(maps.Keys(c)) {
+			if !yield(item, c[item]) {
+				return
+			}
+		}
+	}
+}
+
+// MostCommonSorted is MostCommon for ordered values, with ties broken by
+// ascending value
+func MostCommonSorted[T cmp.Ordered](c Counter[T], n int) []CounterEntry[T] {
+	return mostCommon(SortedCounts(c), n)
+}
+
+// mostCommon stably sorts the counts by descending count and keeps the first n
+func mostCommon[T comparable](counts iter.Seq2[T, int], n int) []CounterEntry[T] {
+	entries := []CounterEntry[T]{}
+	for item, count := range counts {
+		entries = append(entries, CounterEntry[T]{item, count})
+	}
+	slices.SortStableFunc(entries, func(a, b CounterEntry[T]) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	if n >= 0 && n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries
+}
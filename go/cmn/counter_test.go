@@ -0,0 +1,90 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: counter_test
+	Description: Tests for the Counter multiset
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCounterCounts(t *testing.T) {
+	c := NewCounter("a", "b", "a", "c", "a", "b")
+	if c.Count("a") != 3 || c.Count("z") != 0 || c.Total() != 6 || c.Len() != 3 {
+		t.Errorf("unexpected counts: %v", c)
+	}
+
+	c.AddN("c", -5)
+	if c.Len() != 2 || c.Count("c") != 0 {
+		t.Errorf("expected c to be removed, got %v", c)
+	}
+
+	fromSeq := CounterOf(slices.Values([]int{4, 4, 2}))
+	if !maps.Equal(fromSeq, Counter[int]{4: 2, 2: 1}) {
+		t.Errorf("unexpected counter from sequence: %v", fromSeq)
+	}
+}
+
+func TestCounterMergeSubtract(t *testing.T) {
+	c := NewCounter(1, 1, 2)
+	c.Merge(NewCounter(2, 3))
+	if !maps.Equal(c, Counter[int]{1: 2, 2: 2, 3: 1}) {
+		t.Errorf("unexpected merge: %v", c)
+	}
+
+	c.Subtract(NewCounter(1, 3, 3))
+	if !maps.Equal(c, Counter[int]{1: 1, 2: 2}) {
+		t.Errorf("unexpected subtraction: %v", c)
+	}
+}
+
+func TestCounterMostCommon(t *testing.T) {
+	c := NewCounter("x", "y", "y", "z", "z", "w", "w", "w")
+
+	top := c.MostCommon(1)
+	if len(top) != 1 || top[0] != (CounterEntry[string]{"w", 3}) {
+		t.Errorf("unexpected most common: %v", top)
+	}
+
+	sorted := MostCommonSorted(c, -1)
+	want := []CounterEntry[string]{{"w", 3}, {"y", 2}, {"z", 2}, {"x", 1}}
+	if !slices.Equal(sorted, want) {
+		t.Errorf("expected %v, got %v", want, sorted)
+	}
+
+	keys := []string{}
+	for key := range SortedCounts(c) {
+		keys = append(keys, key)
+	}
+	if !slices.Equal(keys, []string{"w", "x", "y", "z"}) {
+		t.Errorf("unexpected sorted iteration: %v", keys)
+	}
+}
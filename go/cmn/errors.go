@@ -30,28 +30,185 @@ CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 */
 package cmn
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
-type ActiveCmdUndefinedError struct {
+// InvalidDataError describes a problem with the puzzle data, rendered like a
+// compiler diagnostic with a caret under the offending text when the column is
+// known
+type InvalidDataError struct {
+	Path     string // Path is the path of the data, empty if unknown
+	Line     string // Line is the text of the offending line
+	LineNum  int    // LineNum is the 1-based line number, 0 if unknown
+	Col      int    // Col is the 1-based byte column the problem starts at, 0 if unknown
+	EndCol   int    // EndCol is the column after the offending text, the caret spans at least Col
+	Expected string // Expected describes what should have been found
+	Found    string // Found describes what was found instead
+	Err      error  // Err is the underlying error, if any
 }
 
-func (e *ActiveCmdUndefinedError) Error() string {
-	return fmt.Sprintf("ERROR: Active command not defined\n")
+func (e *InvalidDataError) Error() string {
+	location := e.Path
+	if location == "" {
+		location = "input"
+	}
+	if e.LineNum > 0 {
+		location += fmt.Sprintf(":%d", e.LineNum)
+		if e.Col > 0 {
+			location += fmt.Sprintf(":%d", e.Col)
+		}
+	}
+
+	message := location + ": invalid data"
+	if e.Expected != "" {
+		message += ": expected " + e.Expected
+		if e.Found != "" {
+			message += ", found " + e.Found
+		}
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	message += "\n"
+
+	if e.Line == "" && e.Col == 0 {
+		return message
+	}
+
+	gutter := ""
+	if e.LineNum > 0 {
+		gutter = fmt.Sprint(e.LineNum)
+	}
+	message += fmt.Sprintf(" %s | %s\n", gutter, e.Line)
+	if e.Col > 0 {
+		width := max(e.EndCol-e.Col, 1)
+		message += fmt.Sprintf(" %s | %s%s\n", strings.Repeat(" ", len(gutter)), strings.Repeat(" ", e.Col-1), strings.Repeat("^", width))
+	}
+	return message
 }
 
-type InvalidDataError struct {
-	Line string
-	Err  error
+func (e *InvalidDataError) Unwrap() error {
+	return e.Err
 }
 
-func (e *InvalidDataError) Error() string {
-	return fmt.Sprintf("invalid data found in line: %s\nErr Message:\n%v\n", e.Line, e.Err)
+// FieldDataError creates an InvalidDataError for the text starting at the
+// 0-based byte offset of the line
+func FieldDataError(line string, offset int, text, expected string) *InvalidDataError {
+	return &InvalidDataError{
+		Line:     line,
+		Col:      offset + 1,
+		EndCol:   offset + 1 + len(text),
+		Expected: expected,
+		Found:    strconv.Quote(text),
+	}
 }
 
 type SolverUndefinedError struct {
+	DayNum    int
 	PuzzleNum int
+	Hint      string // Hint is appended to the message, e.g. where to find the defined solvers
 }
 
 func (e *SolverUndefinedError) Error() string {
-	return fmt.Sprintf("ERROR: Solver not defined for puzzle %d\n", e.PuzzleNum)
+	hint := ""
+	if e.Hint != "" {
+		hint = ", " + e.Hint
+	}
+	if e.DayNum > 0 {
+		return fmt.Sprintf("ERROR: Solver not defined for day %d puzzle %d%s\n", e.DayNum, e.PuzzleNum, hint)
+	}
+	return fmt.Sprintf("ERROR: Solver not defined for puzzle %d%s\n", e.PuzzleNum, hint)
+}
+
+type SessionUndefinedError struct {
+}
+
+func (e *SessionUndefinedError) Error() string {
+	return fmt.Sprintf("ERROR: Session token not defined, set %s or save it to <config dir>/%s\n", SessionEnvVar, SessionConfigFile)
+}
+
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("ERROR: Request to %s failed with status %d\n%s\n", e.URL, e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+type AnswerRejectedError struct {
+	Day    int
+	Part   int
+	Answer string
+	Reason string
+}
+
+func (e *AnswerRejectedError) Error() string {
+	return fmt.Sprintf("ERROR: Answer %s for day %d part %d rejected: %s\n", e.Answer, e.Day, e.Part, e.Reason)
+}
+
+type InputNotFoundError struct {
+	Path string
+	Err  error
+}
+
+func (e *InputNotFoundError) Error() string {
+	return fmt.Sprintf("ERROR: Puzzle data not found at %s, download it with the fetch command or pass --input\n", e.Path)
+}
+
+func (e *InputNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+type AnswerMismatchError struct {
+	DayNum    int
+	PuzzleNum int
+	IsSample  bool
+	Expected  string
+	Answer    string
+}
+
+func (e *AnswerMismatchError) Error() string {
+	data := "puzzle"
+	if e.IsSample {
+		data = "sample"
+	}
+	answer := e.Answer
+	if answer == "" {
+		answer = "<none>"
+	}
+
+	// Numeric answers also show how far off they are
+	diff := ""
+	expected, expectedErr := strconv.Atoi(e.Expected)
+	actual, actualErr := strconv.Atoi(e.Answer)
+	if expectedErr == nil && actualErr == nil {
+		diff = fmt.Sprintf(" (%+d)", actual-expected)
+	}
+
+	return fmt.Sprintf(
+		"ERROR: Day %d puzzle %d %s answer does not match the expected answer\n- expected: %s\n+ answer:   %s%s\n",
+		e.DayNum, e.PuzzleNum, data, e.Expected, answer, diff,
+	)
+}
+
+type CancelledError struct {
+	DayNum    int
+	PuzzleNum int
+	Elapsed   time.Duration
+	LinesRead int
+	Err       error
+}
+
+func (e *CancelledError) Error() string {
+	return fmt.Sprintf("ERROR: Day %d puzzle %d cancelled after %s, %d lines read: %v\n", e.DayNum, e.PuzzleNum, e.Elapsed.Round(time.Millisecond), e.LinesRead, e.Err)
+}
+
+func (e *CancelledError) Unwrap() error {
+	return e.Err
 }
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: exit
+	Description: Process exit codes for the errors returned by the commands
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"context"
+	"errors"
+)
+
+// Exit codes returned by the advent command, so that scripts can tell the
+// failures apart
+const (
+	ExitOK            = 0
+	ExitFailure       = 1 // ExitFailure is any error without a more specific code
+	ExitMissingInput  = 3
+	ExitParseFailure  = 4
+	ExitMissingSolver = 5
+	ExitWrongAnswer   = 6
+	ExitTimeout       = 7
+	ExitInterrupted   = 130 // ExitInterrupted follows the shell convention for SIGINT
+)
+
+// ExitCode maps an error returned by a command to the process exit code
+func ExitCode(err error) int {
+	var (
+		inputErr    *InputNotFoundError
+		dataErr     *InvalidDataError
+		solverErr   *SolverUndefinedError
+		mismatchErr *AnswerMismatchError
+		rejectedErr *AnswerRejectedError
+	)
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	case errors.As(err, &inputErr):
+		return ExitMissingInput
+	case errors.As(err, &dataErr):
+		return ExitParseFailure
+	case errors.As(err, &solverErr):
+		return ExitMissingSolver
+	case errors.As(err, &mismatchErr), errors.As(err, &rejectedErr):
+		return ExitWrongAnswer
+	}
+	return ExitFailure
+}
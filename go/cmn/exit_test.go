@@ -0,0 +1,71 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: exit_test
+	Description: Tests for mapping errors to exit codes
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"nil":            {nil, ExitOK},
+		"generic":        {errors.New("boom"), ExitFailure},
+		"missing input":  {&InputNotFoundError{Path: "x", Err: os.ErrNotExist}, ExitMissingInput},
+		"parse failure":  {fmt.Errorf("wrapped: %w", &InvalidDataError{LineNum: 2}), ExitParseFailure},
+		"missing solver": {&SolverUndefinedError{DayNum: 1, PuzzleNum: 3}, ExitMissingSolver},
+		"wrong answer":   {&AnswerMismatchError{Expected: "1", Answer: "2"}, ExitWrongAnswer},
+		"rejected":       {&AnswerRejectedError{Reason: "too high"}, ExitWrongAnswer},
+		"timeout":        {fmt.Errorf("solving: %w", context.DeadlineExceeded), ExitTimeout},
+		"interrupted":    {&CancelledError{Err: context.Canceled}, ExitInterrupted},
+	}
+	for name, test := range tests {
+		if got := ExitCode(test.err); got != test.want {
+			t.Errorf("%s: expected exit code %d, got %d", name, test.want, got)
+		}
+	}
+}
+
+func TestNewHandlerMissingInput(t *testing.T) {
+	handler, err := NewHandlerE(nil, WithPuzzle(1, 1, false), WithInput("does/not/exist.txt"))
+	defer handler.Close()
+
+	var inputErr *InputNotFoundError
+	if !errors.As(err, &inputErr) || inputErr.Path != "does/not/exist.txt" {
+		t.Fatalf("expected an InputNotFoundError, got %v", err)
+	}
+}
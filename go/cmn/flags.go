@@ -30,9 +30,14 @@ CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 */
 package cmn
 
+import "github.com/spf13/cobra"
+
 // GetFlagBool retrieves the bool value of the flag, defaults to false
-func GetFlagBool(flagName string) bool {
-	if value, err := ActiveCmd.Flags().GetBool(flagName); err != nil {
+func GetFlagBool(cmd *cobra.Command, flagName string) bool {
+	if cmd == nil {
+		return false
+	}
+	if value, err := cmd.Flags().GetBool(flagName); err != nil {
 		return false
 	} else {
 		return value
@@ -40,8 +45,11 @@ func GetFlagBool(flagName string) bool {
 }
 
 // GetFlagBoolD retrieves the bool value of the flag, defaults to the provided value
-func GetFlagBoolD(flagName string, defaultVal bool) bool {
-	if value, err := ActiveCmd.Flags().GetBool(flagName); err != nil {
+func GetFlagBoolD(cmd *cobra.Command, flagName string, defaultVal bool) bool {
+	if cmd == nil {
+		return defaultVal
+	}
+	if value, err := cmd.Flags().GetBool(flagName); err != nil {
 		return defaultVal
 	} else {
 		return value
@@ -49,8 +57,11 @@ func GetFlagBoolD(flagName string, defaultVal bool) bool {
 }
 
 // GetFlagInt retrieves the int value of the flag, defaults to 0
-func GetFlagInt(flagName string) int {
-	if value, err := ActiveCmd.Flags().GetInt(flagName); err != nil {
+func GetFlagInt(cmd *cobra.Command, flagName string) int {
+	if cmd == nil {
+		return 0
+	}
+	if value, err := cmd.Flags().GetInt(flagName); err != nil {
 		return 0
 	} else {
 		return value
@@ -58,8 +69,11 @@ func GetFlagInt(flagName string) int {
 }
 
 // GetFlagIntD retrieves the int value of the flag, defaults to the provided value
-func GetFlagIntD(flagName string, defaultVal int) int {
-	if value, err := ActiveCmd.Flags().GetInt(flagName); err != nil {
+func GetFlagIntD(cmd *cobra.Command, flagName string, defaultVal int) int {
+	if cmd == nil {
+		return defaultVal
+	}
+	if value, err := cmd.Flags().GetInt(flagName); err != nil {
 		return defaultVal
 	} else {
 		return value
@@ -67,8 +81,11 @@ func GetFlagIntD(flagName string, defaultVal int) int {
 }
 
 // GetFlagString retrieves the string value of the flag, defaults to ""
-func GetFlagString(flagName string) string {
-	if value, err := ActiveCmd.Flags().GetString(flagName); err != nil {
+func GetFlagString(cmd *cobra.Command, flagName string) string {
+	if cmd == nil {
+		return ""
+	}
+	if value, err := cmd.Flags().GetString(flagName); err != nil {
 		return ""
 	} else {
 		return value
@@ -76,8 +93,11 @@ func GetFlagString(flagName string) string {
 }
 
 // GetFlagStringD retrieves the string value of the flag, defaults to the provided value
-func GetFlagStringD(flagName, defaultVal string) string {
-	if value, err := ActiveCmd.Flags().GetString(flagName); err != nil {
+func GetFlagStringD(cmd *cobra.Command, flagName, defaultVal string) string {
+	if cmd == nil {
+		return defaultVal
+	}
+	if value, err := cmd.Flags().GetString(flagName); err != nil {
 		return defaultVal
 	} else {
 		return value
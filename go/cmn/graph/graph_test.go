@@ -0,0 +1,201 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: graph
+	Title: graph_test
+	Description: Tests for the priority queue and graph searches
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package graph
+
+import (
+	"advent/cmn/grid"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// maze is open everywhere except #, with two equally short routes around the
+// center wall from S to E
+const maze = `S...
+.##.
+...E`
+
+func parseMaze(t *testing.T) (*grid.Grid[rune], grid.Point, grid.Point) {
+	t.Helper()
+	rows := [][]rune{}
+	for _, line := range strings.Split(maze, "\n") {
+		rows = append(rows, []rune(line))
+	}
+	g, err := grid.FromRows(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, _ := g.Find(func(r rune) bool { return r == 'S' })
+	end, _ := g.Find(func(r rune) bool { return r == 'E' })
+	return g, start, end
+}
+
+func openNeighbors(g *grid.Grid[rune]) NeighborsFunc[grid.Point] {
+	return func(p grid.Point) []grid.Point {
+		open := []grid.Point{}
+		for _, next := range g.Neighbors4(p) {
+			if g.At(next) != '#' {
+				open = append(open, next)
+			}
+		}
+		return open
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	queue := NewPriorityQueue[string]()
+	queue.Push("c", 3)
+	queue.Push("a", 1)
+	queue.Push("b", 2)
+	queue.Push("a2", 1)
+
+	if value, priority, ok := queue.Peek(); !ok || value != "a" || priority != 1 {
+		t.Errorf("unexpected peek: %v %v %v", value, priority, ok)
+	}
+
+	got := []string{}
+	for queue.Len() > 0 {
+		value, _ := queue.Pop()
+		got = append(got, value)
+	}
+	if strings.Join(got, ",") != "a,a2,b,c" {
+		t.Errorf("unexpected pop order: %v", got)
+	}
+}
+
+func TestBFS(t *testing.T) {
+	g, start, end := parseMaze(t)
+	result, err := BFS(context.Background(), start, openNeighbors(g), func(p grid.Point) bool { return p == end })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Found || result.Goal != end {
+		t.Fatal("expected the end to be found")
+	}
+	if cost, _ := result.Cost(end); cost != 5 {
+		t.Errorf("expected a cost of 5, got %d", cost)
+	}
+	if path := result.Path(end); len(path) != 6 || path[0] != start || path[5] != end {
+		t.Errorf("unexpected path: %v", path)
+	}
+	if paths := result.AllPaths(end); len(paths) != 2 {
+		t.Errorf("expected 2 shortest paths, got %v", paths)
+	}
+}
+
+func TestDijkstraAndAStar(t *testing.T) {
+	g, start, end := parseMaze(t)
+	neighbors := openNeighbors(g)
+
+	// Entering the bottom row costs 5, so the route along the top is cheaper
+	edges := func(p grid.Point) []Edge[grid.Point] {
+		out := []Edge[grid.Point]{}
+		for _, next := range neighbors(p) {
+			cost := 1
+			if next.Y == g.Height-1 {
+				cost = 5
+			}
+			out = append(out, Edge[grid.Point]{To: next, Cost: cost})
+		}
+		return out
+	}
+	isGoal := func(p grid.Point) bool { return p == end }
+
+	dijkstra, err := Dijkstra(context.Background(), start, edges, isGoal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	astar, err := AStar(context.Background(), start, edges, func(p grid.Point) int { return p.Manhattan(end) }, isGoal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, result := range map[string]*Result[grid.Point]{"dijkstra": dijkstra, "astar": astar} {
+		if cost, ok := result.Cost(end); !ok || cost != 9 {
+			t.Errorf("%s: expected a cost of 9, got %d", name, cost)
+		}
+		if paths := result.AllPaths(end); len(paths) != 1 {
+			t.Errorf("%s: expected a single optimal path, got %v", name, paths)
+		}
+		if states := result.PathStates(end); len(states) != 6 || !states[grid.Point{X: 3, Y: 0}] {
+			t.Errorf("%s: unexpected path states: %v", name, states)
+		}
+	}
+}
+
+func TestDijkstraAllPathsUnitCost(t *testing.T) {
+	g, start, end := parseMaze(t)
+	neighbors := openNeighbors(g)
+	edges := func(p grid.Point) []Edge[grid.Point] {
+		out := []Edge[grid.Point]{}
+		for _, next := range neighbors(p) {
+			out = append(out, Edge[grid.Point]{To: next, Cost: 1})
+		}
+		return out
+	}
+
+	result, err := Dijkstra(context.Background(), start, edges, func(p grid.Point) bool { return p == end })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths := result.AllPaths(end); len(paths) != 2 {
+		t.Errorf("expected 2 optimal paths, got %v", paths)
+	}
+	if states := result.PathStates(end); len(states) != 10 {
+		t.Errorf("expected every open cell on an optimal path, got %v", states)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	result, _ := BFS(context.Background(), 0, func(int) []int { return nil }, func(s int) bool { return s == 1 })
+	if result.Found || result.Path(1) != nil {
+		t.Error("expected the goal to be unreachable")
+	}
+}
+
+func TestSearchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// An endless line of states, the search only stops because it's cancelled
+	next := func(s int) []int { return []int{s + 1} }
+	result, err := BFS(ctx, 0, next, nil)
+	if !errors.Is(err, context.Canceled) || len(result.Dist) == 0 {
+		t.Errorf("expected a partial BFS result and context.Canceled, got %v", err)
+	}
+
+	edges := func(s int) []Edge[int] { return []Edge[int]{{To: s + 1, Cost: 1}} }
+	if _, err := Dijkstra(ctx, 0, edges, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Dijkstra to return context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: graph
+	Title: pqueue
+	Description: Priority queue built on container/heap
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package graph
+
+import "container/heap"
+
+// PriorityQueue is a min-priority queue, values with the lowest priority are
+// popped first and equal priorities are popped in insertion order
+type PriorityQueue[T any] struct {
+	items pqItems[T]
+	seq   int
+}
+
+type pqItem[T any] struct {
+	value    T
+	priority int
+	seq      int
+}
+
+// pqItems implements heap.Interface
+type pqItems[T any] []pqItem[T]
+
+func (q pqItems[T]) Len() int { return len(q) }
+
+func (q pqItems[T]) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q pqItems[T]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *pqItems[T]) Push(x any) { *q = append(*q, x.(pqItem[T])) }
+
+func (q *pqItems[T]) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// NewPriorityQueue creates an empty priority queue
+func NewPriorityQueue[T any]() *PriorityQueue[T] {
+	return &PriorityQueue[T]{}
+}
+
+// Push adds the value with the given priority
+func (q *PriorityQueue[T]) Push(value T, priority int) {
+	heap.Push(&q.items, pqItem[T]{value: value, priority: priority, seq: q.seq})
+	q.seq++
+}
+
+// Pop removes and returns the value with the lowest priority, it panics if the
+// queue is empty
+func (q *PriorityQueue[T]) Pop() (value T, priority int) {
+	item := heap.Pop(&q.items).(pqItem[T])
+	return item.value, item.priority
+}
+
+// Peek returns the value with the lowest priority without removing it, ok is
+// false if the queue is empty
+func (q *PriorityQueue[T]) Peek() (value T, priority int, ok bool) {
+	if len(q.items) == 0 {
+		return value, 0, false
+	}
+	return q.items[0].value, q.items[0].priority, true
+}
+
+// Len returns the number of values in the queue
+func (q *PriorityQueue[T]) Len() int {
+	return len(q.items)
+}
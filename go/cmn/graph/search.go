@@ -0,0 +1,246 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: graph
+	Title: search
+	Description: Breadth first, Dijkstra and A* searches over generic states
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package graph
+
+import "context"
+
+// Edge is a weighted step to a neighboring state
+type Edge[S comparable] struct {
+	To   S
+	Cost int
+}
+
+// NeighborsFunc returns the states reachable in a single unweighted step
+type NeighborsFunc[S comparable] func(S) []S
+
+// EdgesFunc returns the weighted steps out of a state
+type EdgesFunc[S comparable] func(S) []Edge[S]
+
+// GoalFunc checks if a state is a goal, a nil GoalFunc searches the whole
+// reachable graph
+type GoalFunc[S comparable] func(S) bool
+
+// HeuristicFunc estimates the remaining cost from a state to the nearest goal.
+// A* only finds optimal paths if it never overestimates and is consistent.
+type HeuristicFunc[S comparable] func(S) int
+
+// Result holds the costs and optimal predecessors found by a search
+type Result[S comparable] struct {
+	Start S         // Start is the state the search started from
+	Goal  S         // Goal is the first goal state reached, if Found
+	Found bool      // Found is set if a goal state was reached
+	Dist  map[S]int // Dist is the lowest cost found to each visited state
+	Prev  map[S][]S // Prev is every predecessor of each state on an optimal path to it
+}
+
+// checkEvery is the number of states expanded between checks for cancellation
+const checkEvery = 1024
+
+func newResult[S comparable](start S) *Result[S] {
+	return &Result[S]{
+		Start: start,
+		Dist:  map[S]int{start: 0},
+		Prev:  map[S][]S{},
+	}
+}
+
+// Cost returns the lowest cost to the state, ok is false if it wasn't reached
+func (r *Result[S]) Cost(to S) (cost int, ok bool) {
+	cost, ok = r.Dist[to]
+	return cost, ok
+}
+
+// Path returns an optimal path from the start to the state, inclusive, or nil
+// if it wasn't reached
+func (r *Result[S]) Path(to S) []S {
+	if _, ok := r.Dist[to]; !ok {
+		return nil
+	}
+
+	path := []S{to}
+	for cur := to; cur != r.Start; {
+		cur = r.Prev[cur][0]
+		path = append(path, cur)
+	}
+	reverse(path)
+	return path
+}
+
+// AllPaths returns every optimal path from the start to the state. The number
+// of paths can grow exponentially, use PathStates if only the states matter.
+func (r *Result[S]) AllPaths(to S) [][]S {
+	if _, ok := r.Dist[to]; !ok {
+		return nil
+	}
+
+	paths := [][]S{}
+	var walk func(cur S, suffix []S)
+	walk = func(cur S, suffix []S) {
+		suffix = append(suffix, cur)
+		if cur == r.Start {
+			path := make([]S, len(suffix))
+			copy(path, suffix)
+			reverse(path)
+			paths = append(paths, path)
+			return
+		}
+		for _, prev := range r.Prev[cur] {
+			walk(prev, suffix)
+		}
+	}
+	walk(to, nil)
+	return paths
+}
+
+// PathStates returns the set of states on any optimal path from the start to
+// the state, inclusive
+func (r *Result[S]) PathStates(to S) map[S]bool {
+	states := map[S]bool{}
+	if _, ok := r.Dist[to]; !ok {
+		return states
+	}
+
+	stack := []S{to}
+	states[to] = true
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, prev := range r.Prev[cur] {
+			if !states[prev] {
+				states[prev] = true
+				stack = append(stack, prev)
+			}
+		}
+	}
+	return states
+}
+
+// BFS searches outwards from the start one unweighted step at a time, stopping
+// once every state as close as the first goal reached has been expanded. If
+// the context is done the partial result is returned with its error.
+func BFS[S comparable](ctx context.Context, start S, neighbors NeighborsFunc[S], isGoal GoalFunc[S]) (*Result[S], error) {
+	result := newResult(start)
+	queue := []S{start}
+
+	for expanded := 1; len(queue) > 0; expanded++ {
+		if expanded%checkEvery == 0 && ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		cur := queue[0]
+		queue = queue[1:]
+		dist := result.Dist[cur]
+
+		if result.Found && dist >= result.Dist[result.Goal] {
+			break
+		}
+		if !result.Found && isGoal != nil && isGoal(cur) {
+			result.Goal, result.Found = cur, true
+			continue
+		}
+
+		for _, next := range neighbors(cur) {
+			nextDist, seen := result.Dist[next]
+			switch {
+			case !seen:
+				result.Dist[next] = dist + 1
+				result.Prev[next] = []S{cur}
+				queue = append(queue, next)
+			case nextDist == dist+1:
+				result.Prev[next] = append(result.Prev[next], cur)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Dijkstra finds the lowest cost paths from the start over non-negative edge
+// costs, stopping once every state as cheap as the first goal reached has been
+// expanded. If the context is done the partial result is returned with its
+// error.
+func Dijkstra[S comparable](ctx context.Context, start S, edges EdgesFunc[S], isGoal GoalFunc[S]) (*Result[S], error) {
+	return AStar(ctx, start, edges, nil, isGoal)
+}
+
+// AStar is Dijkstra's algorithm guided by the heuristic, a nil heuristic
+// behaves exactly like Dijkstra
+func AStar[S comparable](ctx context.Context, start S, edges EdgesFunc[S], heuristic HeuristicFunc[S], isGoal GoalFunc[S]) (*Result[S], error) {
+	estimate := func(s S) int {
+		if heuristic == nil {
+			return 0
+		}
+		return heuristic(s)
+	}
+
+	result := newResult(start)
+	closed := map[S]bool{}
+	queue := NewPriorityQueue[S]()
+	queue.Push(start, estimate(start))
+
+	for expanded := 1; queue.Len() > 0; expanded++ {
+		if expanded%checkEvery == 0 && ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		cur, priority := queue.Pop()
+		if closed[cur] {
+			continue
+		}
+		if result.Found && priority > result.Dist[result.Goal] {
+			break
+		}
+		closed[cur] = true
+
+		dist := result.Dist[cur]
+		if !result.Found && isGoal != nil && isGoal(cur) {
+			result.Goal, result.Found = cur, true
+			continue
+		}
+
+		for _, edge := range edges(cur) {
+			nextDist := dist + edge.Cost
+			bestDist, seen := result.Dist[edge.To]
+			switch {
+			case !seen || nextDist < bestDist:
+				result.Dist[edge.To] = nextDist
+				result.Prev[edge.To] = []S{cur}
+				queue.Push(edge.To, nextDist+estimate(edge.To))
+			case nextDist == bestDist && edge.To != start:
+				result.Prev[edge.To] = append(result.Prev[edge.To], cur)
+			}
+		}
+	}
+	return result, nil
+}
+
+func reverse[S any](values []S) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: graph
+	Title: topo
+	Description: Directed graphs with topological sorting and cycle detection
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Digraph is a directed graph, nodes and edges are kept in insertion order so
+// sorts are deterministic
+type Digraph[N comparable] struct {
+	nodes []N
+	succ  map[N][]N
+	edges map[[2]N]bool
+}
+
+// CycleError is returned when a graph that must be acyclic has a cycle
+type CycleError[N comparable] struct {
+	Cycle []N // Cycle is the nodes of a cycle, each with an edge to the next and the last to the first
+}
+
+func (e *CycleError[N]) Error() string {
+	parts := make([]string, 0, len(e.Cycle)+1)
+	for _, node := range e.Cycle {
+		parts = append(parts, fmt.Sprint(node))
+	}
+	if len(e.Cycle) > 0 {
+		parts = append(parts, fmt.Sprint(e.Cycle[0]))
+	}
+	return "cycle detected: " + strings.Join(parts, " -> ")
+}
+
+// NewDigraph creates an empty directed graph
+func NewDigraph[N comparable]() *Digraph[N] {
+	return &Digraph[N]{succ: map[N][]N{}, edges: map[[2]N]bool{}}
+}
+
+// AddNode adds the node if it isn't already in the graph
+func (g *Digraph[N]) AddNode(node N) {
+	if _, exists := g.succ[node]; !exists {
+		g.nodes = append(g.nodes, node)
+		g.succ[node] = nil
+	}
+}
+
+// AddEdge adds the nodes and an edge between them, e.g. a rule that from must
+// come before to
+func (g *Digraph[N]) AddEdge(from, to N) {
+	g.AddNode(from)
+	g.AddNode(to)
+	if !g.edges[[2]N{from, to}] {
+		g.edges[[2]N{from, to}] = true
+		g.succ[from] = append(g.succ[from], to)
+	}
+}
+
+// HasEdge checks for an edge from one node to the other
+func (g *Digraph[N]) HasEdge(from, to N) bool {
+	return g.edges[[2]N{from, to}]
+}
+
+// Nodes returns the graph's nodes in insertion order
+func (g *Digraph[N]) Nodes() []N {
+	return append([]N{}, g.nodes...)
+}
+
+// Successors returns the nodes the node has edges to
+func (g *Digraph[N]) Successors(node N) []N {
+	return append([]N{}, g.succ[node]...)
+}
+
+// Induced returns the sub-graph of the given nodes and the edges between them,
+// nodes not in the graph are added without edges
+func (g *Digraph[N]) Induced(nodes []N) *Digraph[N] {
+	sub := NewDigraph[N]()
+	for _, node := range nodes {
+		sub.AddNode(node)
+	}
+	for _, from := range sub.nodes {
+		for _, to := range g.succ[from] {
+			if _, exists := sub.succ[to]; exists {
+				sub.AddEdge(from, to)
+			}
+		}
+	}
+	return sub
+}
+
+// TopoSort orders the nodes so every edge points forwards using Kahn's
+// algorithm, returning a CycleError if that's impossible
+func (g *Digraph[N]) TopoSort() ([]N, error) {
+	inDegree := make(map[N]int, len(g.nodes))
+	for _, node := range g.nodes {
+		for _, next := range g.succ[node] {
+			inDegree[next]++
+		}
+	}
+
+	queue := []N{}
+	for _, node := range g.nodes {
+		if inDegree[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	sorted := make([]N, 0, len(g.nodes))
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, node)
+		for _, next := range g.succ[node] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if len(sorted) < len(g.nodes) {
+		return sorted, &CycleError[N]{Cycle: g.FindCycle()}
+	}
+	return sorted, nil
+}
+
+// SortSubset topologically sorts the given nodes using only the edges between
+// them, which may succeed even if the whole graph has cycles
+func (g *Digraph[N]) SortSubset(nodes []N) ([]N, error) {
+	return g.Induced(nodes).TopoSort()
+}
+
+// FindCycle returns the nodes of a cycle in the graph, or nil if it's acyclic
+func (g *Digraph[N]) FindCycle() []N {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[N]int, len(g.nodes))
+	stack := []N{}
+
+	var visit func(node N) []N
+	visit = func(node N) []N {
+		state[node] = visiting
+		stack = append(stack, node)
+		for _, next := range g.succ[node] {
+			switch state[next] {
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						return append([]N{}, stack[i:]...)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = done
+		return nil
+	}
+
+	for _, node := range g.nodes {
+		if state[node] == unvisited {
+			if cycle := visit(node); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Compare orders two nodes by the edge between them, for use with
+// slices.SortFunc: -1 if a must come before b, 1 if after and 0 if unrelated.
+// This only gives a consistent sort if the edges between the sorted nodes are
+// total, otherwise use SortSubset.
+func (g *Digraph[N]) Compare(a, b N) int {
+	switch {
+	case g.HasEdge(a, b):
+		return -1
+	case g.HasEdge(b, a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsOrdered checks that no edge between the nodes points backwards
+func (g *Digraph[N]) IsOrdered(nodes []N) bool {
+	for i, later := range nodes {
+		for _, earlier := range nodes[:i] {
+			if g.HasEdge(later, earlier) {
+				return false
+			}
+		}
+	}
+	return true
+}
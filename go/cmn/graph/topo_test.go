@@ -0,0 +1,97 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: graph
+	Title: topo_test
+	Description: Tests for topological sorting and cycle detection
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTopoSort(t *testing.T) {
+	g := NewDigraph[string]()
+	g.AddEdge("shirt", "tie")
+	g.AddEdge("tie", "jacket")
+	g.AddEdge("pants", "shoes")
+	g.AddEdge("pants", "belt")
+	g.AddEdge("belt", "jacket")
+	g.AddNode("watch")
+
+	sorted, err := g.TopoSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sorted) != 7 || !g.IsOrdered(sorted) {
+		t.Errorf("unexpected order: %v", sorted)
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	g := NewDigraph[int]()
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 2)
+
+	_, err := g.TopoSort()
+	var cycleErr *CycleError[int]
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected a CycleError, got %v", err)
+	}
+	if !slices.Equal(cycleErr.Cycle, []int{2, 3, 4}) {
+		t.Errorf("unexpected cycle: %v", cycleErr.Cycle)
+	}
+	if err.Error() != "cycle detected: 2 -> 3 -> 4 -> 2" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestSortSubsetAndCompare(t *testing.T) {
+	// The rules form a cycle, but every subset missing a node can be sorted
+	g := NewDigraph[int]()
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 1)
+
+	sorted, err := g.SortSubset([]int{3, 2})
+	if err != nil || !slices.Equal(sorted, []int{2, 3}) {
+		t.Errorf("unexpected subset sort: %v %v", sorted, err)
+	}
+	if g.IsOrdered([]int{3, 2}) || !g.IsOrdered([]int{2, 3}) {
+		t.Error("unexpected IsOrdered result")
+	}
+
+	pages := []int{3, 2}
+	slices.SortFunc(pages, g.Compare)
+	if !slices.Equal(pages, []int{2, 3}) {
+		t.Errorf("unexpected comparator sort: %v", pages)
+	}
+}
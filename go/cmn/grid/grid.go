@@ -0,0 +1,271 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: grid
+	Title: grid
+	Description: Generic 2D grid backed by a flat slice
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package grid
+
+import (
+	"advent/cmn"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Grid is a fixed size 2D grid of values stored row by row in a flat slice
+type Grid[T any] struct {
+	Width  int
+	Height int
+	cells  []T
+}
+
+// New creates a grid of zero values
+func New[T any](width, height int) *Grid[T] {
+	return &Grid[T]{Width: width, Height: height, cells: make([]T, width*height)}
+}
+
+// FromRows creates a grid from rows of equal length
+func FromRows[T any](rows [][]T) (*Grid[T], error) {
+	if len(rows) == 0 {
+		return New[T](0, 0), nil
+	}
+
+	g := New[T](len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != g.Width {
+			return nil, fmt.Errorf("row %d has %d cells, expected %d", y, len(row), g.Width)
+		}
+		copy(g.cells[y*g.Width:], row)
+	}
+	return g, nil
+}
+
+// Parse reads the handler's remaining input lines into a grid, converting each
+// rune with parseCell. Lines must be of equal length.
+func Parse[T any](h *cmn.AdventHandler, parseCell func(p Point, r rune) (T, error)) (*Grid[T], error) {
+	firstLine := h.LineNum() + 1
+	lines, err := h.RuneGrid()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]T, len(lines))
+	for y, line := range lines {
+		row := make([]T, 0, len(line))
+		for x, r := range line {
+			value, err := parseCell(Point{x, y}, r)
+			if err != nil {
+				dataErr := cmn.FieldDataError(string(line), len(string(line[:x])), string(r), "a valid cell")
+				dataErr.Err = err
+				return nil, h.DataErrorAt(dataErr, string(line), firstLine+y)
+			}
+			row = append(row, value)
+		}
+		rows[y] = row
+	}
+	return FromRows(rows)
+}
+
+// ParseRunes reads the handler's remaining input lines into a grid of runes
+func ParseRunes(h *cmn.AdventHandler) (*Grid[rune], error) {
+	return Parse(h, func(_ Point, r rune) (rune, error) {
+		return r, nil
+	})
+}
+
+// InBounds checks if the point is on the grid
+func (g *Grid[T]) InBounds(p Point) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < g.Width && p.Y < g.Height
+}
+
+// Get returns the value at the point, ok is false if it's out of bounds
+func (g *Grid[T]) Get(p Point) (value T, ok bool) {
+	if !g.InBounds(p) {
+		return value, false
+	}
+	return g.cells[p.Y*g.Width+p.X], true
+}
+
+// At returns the value at the point, or the zero value if it's out of bounds
+func (g *Grid[T]) At(p Point) T {
+	value, _ := g.Get(p)
+	return value
+}
+
+// Set stores the value at the point, returning false if it's out of bounds
+func (g *Grid[T]) Set(p Point, value T) bool {
+	if !g.InBounds(p) {
+		return false
+	}
+	g.cells[p.Y*g.Width+p.X] = value
+	return true
+}
+
+// All iterates over the grid's points and values row by row
+func (g *Grid[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		for i, value := range g.cells {
+			if !yield(Point{i % g.Width, i / g.Width}, value) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbors returns the in bounds points one step away in each direction
+func (g *Grid[T]) Neighbors(p Point, dirs []Direction) []Point {
+	neighbors := make([]Point, 0, len(dirs))
+	for _, dir := range dirs {
+		if next := p.Add(dir); g.InBounds(next) {
+			neighbors = append(neighbors, next)
+		}
+	}
+	return neighbors
+}
+
+// Neighbors4 returns the in bounds points of the 4-neighborhood
+func (g *Grid[T]) Neighbors4(p Point) []Point {
+	return g.Neighbors(p, Cardinal)
+}
+
+// Neighbors8 returns the in bounds points of the 8-neighborhood
+func (g *Grid[T]) Neighbors8(p Point) []Point {
+	return g.Neighbors(p, AllDirections)
+}
+
+// FindAll returns the points of the values matching the predicate, row by row
+func (g *Grid[T]) FindAll(match func(T) bool) []Point {
+	points := []Point{}
+	for p, value := range g.All() {
+		if match(value) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// Find returns the first point, row by row, with a value matching the predicate
+func (g *Grid[T]) Find(match func(T) bool) (Point, bool) {
+	for p, value := range g.All() {
+		if match(value) {
+			return p, true
+		}
+	}
+	return Point{}, false
+}
+
+// FloodFill returns the region reachable from start by stepping in the given
+// directions between values for which connected returns true
+func (g *Grid[T]) FloodFill(start Point, dirs []Direction, connected func(from, to T) bool) []Point {
+	if !g.InBounds(start) {
+		return nil
+	}
+
+	visited := cmn.NewPlainSet(start)
+	region := []Point{start}
+	for i := 0; i < len(region); i++ {
+		cur := region[i]
+		for _, next := range g.Neighbors(cur, dirs) {
+			if visited.Contains(next) || !connected(g.At(cur), g.At(next)) {
+				continue
+			}
+			visited.Add(next)
+			region = append(region, next)
+		}
+	}
+	return region
+}
+
+// Clone returns a copy of the grid
+func (g *Grid[T]) Clone() *Grid[T] {
+	clone := New[T](g.Width, g.Height)
+	copy(clone.cells, g.cells)
+	return clone
+}
+
+// Transpose returns a copy of the grid mirrored along its main diagonal
+func (g *Grid[T]) Transpose() *Grid[T] {
+	transposed := New[T](g.Height, g.Width)
+	for p, value := range g.All() {
+		transposed.Set(Point{p.Y, p.X}, value)
+	}
+	return transposed
+}
+
+// RotateRight returns a copy of the grid rotated 90 degrees clockwise
+func (g *Grid[T]) RotateRight() *Grid[T] {
+	rotated := New[T](g.Height, g.Width)
+	for p, value := range g.All() {
+		rotated.Set(Point{g.Height - 1 - p.Y, p.X}, value)
+	}
+	return rotated
+}
+
+// RotateLeft returns a copy of the grid rotated 90 degrees counter-clockwise
+func (g *Grid[T]) RotateLeft() *Grid[T] {
+	rotated := New[T](g.Height, g.Width)
+	for p, value := range g.All() {
+		rotated.Set(Point{p.Y, g.Width - 1 - p.X}, value)
+	}
+	return rotated
+}
+
+// Format renders the grid row by row using format for each value
+func (g *Grid[T]) Format(format func(T) string) string {
+	var sb strings.Builder
+	for p, value := range g.All() {
+		sb.WriteString(format(value))
+		if p.X == g.Width-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// String renders the grid, runes and bytes as characters and other values
+// with fmt's default format
+func (g *Grid[T]) String() string {
+	return g.Format(func(value T) string {
+		switch v := any(value).(type) {
+		case rune:
+			return string(v)
+		case byte:
+			return string(rune(v))
+		case string:
+			return v
+		default:
+			return fmt.Sprint(v)
+		}
+	})
+}
+
+// Print writes the rendered grid
+func (g *Grid[T]) Print(w io.Writer) {
+	fmt.Fprint(w, g.String())
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: grid
+	Title: grid_test
+	Description: Tests for the generic 2D grid
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package grid
+
+import (
+	"advent/cmn"
+	"io"
+	"strings"
+	"testing"
+)
+
+func parseGrid(t *testing.T, data string) *Grid[rune] {
+	t.Helper()
+	handler, err := cmn.NewHandlerE(nil,
+		cmn.WithPuzzle(1, 1, false),
+		cmn.WithReader(strings.NewReader(data)),
+		cmn.WithOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(handler.Close)
+
+	g, err := ParseRunes(handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestParseAndBounds(t *testing.T) {
+	g := parseGrid(t, "abc\ndef\n")
+	if g.Width != 3 || g.Height != 2 {
+		t.Fatalf("expected a 3x2 grid, got %dx%d", g.Width, g.Height)
+	}
+	if value, ok := g.Get(Point{2, 1}); !ok || value != 'f' {
+		t.Errorf("expected f at 2,1, got %q", value)
+	}
+	if _, ok := g.Get(Point{3, 0}); ok {
+		t.Error("expected 3,0 to be out of bounds")
+	}
+	if len(g.Neighbors8(Point{0, 0})) != 3 || len(g.Neighbors4(Point{1, 0})) != 3 {
+		t.Error("unexpected neighbor counts at the edges")
+	}
+}
+
+func TestParseRagged(t *testing.T) {
+	handler, err := cmn.NewHandlerE(nil,
+		cmn.WithPuzzle(1, 1, false),
+		cmn.WithReader(strings.NewReader("abc\nde\n")),
+		cmn.WithOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer handler.Close()
+
+	if _, err := ParseRunes(handler); err == nil {
+		t.Error("expected an error for rows of different lengths")
+	}
+}
+
+func TestRotateAndTranspose(t *testing.T) {
+	g := parseGrid(t, "ab\ncd\nef\n")
+
+	tests := map[string]struct {
+		got  *Grid[rune]
+		want string
+	}{
+		"transpose":    {g.Transpose(), "ace\nbdf\n"},
+		"rotate right": {g.RotateRight(), "eca\nfdb\n"},
+		"rotate left":  {g.RotateLeft(), "bdf\nace\n"},
+	}
+	for name, test := range tests {
+		if got := test.got.String(); got != test.want {
+			t.Errorf("%s: expected %q, got %q", name, test.want, got)
+		}
+	}
+}
+
+func TestFloodFillAndFind(t *testing.T) {
+	g := parseGrid(t, "aab\nabb\nccb\n")
+	same := func(from, to rune) bool { return from == to }
+
+	if region := g.FloodFill(Point{0, 0}, Cardinal, same); len(region) != 3 {
+		t.Errorf("expected the a region to have 3 points, got %v", region)
+	}
+	if region := g.FloodFill(Point{2, 0}, Cardinal, same); len(region) != 4 {
+		t.Errorf("expected the b region to have 4 points, got %v", region)
+	}
+
+	cs := g.FindAll(func(r rune) bool { return r == 'c' })
+	if len(cs) != 2 || cs[0] != (Point{0, 2}) {
+		t.Errorf("unexpected c points: %v", cs)
+	}
+}
+
+func TestDirections(t *testing.T) {
+	if North.TurnRight() != East || North.TurnLeft() != West || North.Opposite() != South {
+		t.Error("unexpected turns from North")
+	}
+	if (Point{1, 1}).Move(SouthEast, 2) != (Point{3, 3}) {
+		t.Error("unexpected move result")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: grid
+	Title: point
+	Description: Points and directions on a 2D grid
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package grid
+
+import "fmt"
+
+// Point is a position on a grid, Y increases downwards
+type Point struct {
+	X int
+	Y int
+}
+
+// Direction is a single step between neighboring points
+type Direction struct {
+	DX int
+	DY int
+}
+
+var (
+	North     = Direction{0, -1}
+	NorthEast = Direction{1, -1}
+	East      = Direction{1, 0}
+	SouthEast = Direction{1, 1}
+	South     = Direction{0, 1}
+	SouthWest = Direction{-1, 1}
+	West      = Direction{-1, 0}
+	NorthWest = Direction{-1, -1}
+)
+
+// Cardinal is the 4-neighborhood, clockwise from North
+var Cardinal = []Direction{North, East, South, West}
+
+// Diagonal is the diagonal directions, clockwise from NorthEast
+var Diagonal = []Direction{NorthEast, SouthEast, SouthWest, NorthWest}
+
+// AllDirections is the 8-neighborhood, clockwise from North
+var AllDirections = []Direction{North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest}
+
+// Add returns the point one step away in the direction
+func (p Point) Add(d Direction) Point {
+	return Point{p.X + d.DX, p.Y + d.DY}
+}
+
+// Move returns the point n steps away in the direction
+func (p Point) Move(d Direction, n int) Point {
+	return Point{p.X + d.DX*n, p.Y + d.DY*n}
+}
+
+// Manhattan returns the Manhattan distance between the points
+func (p Point) Manhattan(other Point) int {
+	dx, dy := p.X-other.X, p.Y-other.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+// TurnRight returns the direction rotated 90 degrees clockwise
+func (d Direction) TurnRight() Direction {
+	return Direction{-d.DY, d.DX}
+}
+
+// TurnLeft returns the direction rotated 90 degrees counter-clockwise
+func (d Direction) TurnLeft() Direction {
+	return Direction{d.DY, -d.DX}
+}
+
+// Opposite returns the reversed direction
+func (d Direction) Opposite() Direction {
+	return Direction{-d.DX, -d.DY}
+}
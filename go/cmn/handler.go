@@ -32,10 +32,19 @@ package cmn
 
 import (
 	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
+	"io"
+	"log/slog"
 	"os"
-	"path/filepath"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -50,14 +59,36 @@ type AdventHandler struct {
 	Puzzle     string         // Puzzle is a string representation of the puzzle number, used for file paths
 	PuzzleNum  int            // PuzzleNum is the integer representation of the puzzle number
 	FileStream *os.File       // FileStream is the filestream for the puzzle data
+	InputPath  string         // InputPath is the path of the puzzle data, "-" for stdin
 	Scanner    *bufio.Scanner // Scanner is the bufio.Scanner for the puzzle data
 	IsSample   bool           // IsSample is a boolean flag that determines if the sample data should be used
-
-	solvers      []HandlerFunc  // solvers is a slice of functions that solve the puzzles
-	cmd          *cobra.Command // cmd is a reference to the cobra command that the handler is associated with
-	debugEnabled bool           // debugEnabled is a boolean flag that determines if debug output should be printed
+	Answer     string         // Answer is the answer reported by the solver, empty if none was reported
+	Expected   string         // Expected is the known answer that Solve checks the answer against, empty if unknown
+	Out        io.Writer      // Out is the writer that solver output is printed to, defaults to os.Stdout
+	Log        *slog.Logger   // Log is the logger for the day, with the day as its component
+
+	solvers     []HandlerFunc   // solvers is a slice of functions that solve the puzzles
+	inputCloser io.Closer       // inputCloser closes any decompression reader wrapping the filestream
+	inputReader io.Reader       // inputReader is an in-memory source of the puzzle data, used instead of a file
+	cmd         *cobra.Command  // cmd is a reference to the cobra command that the handler is associated with
+	spans       SpanTracker     // spans tracks the nested profiling spans of the solver
+	lineNum     atomic.Int64    // lineNum is the 1-based number of the last line read with Scan
+	ctx         context.Context // ctx is cancelled when the run times out or is interrupted
+	optErr      error           // optErr is the first error encountered applying the options
+	stats       *SpanStats      // stats are the measurements of the last Solve, nil until it has run
+	inputHash   hash.Hash       // inputHash hashes the puzzle data as it is read
+	inputTee    io.Reader       // inputTee is the reader under the scanner that feeds inputHash
+	records     *RecordWriter   // records receives a record of each Solve, nil for the text format
+	start       time.Time       // start is when the handler was created, the start of the run
+	profileOut  io.Writer       // profileOut is the writer profiles are logged to, nil for ProfileOut
+	progressOut io.Writer       // progressOut is the writer progress is rendered to, nil for ProgressOut
+	abandoned   bool            // abandoned is set if Solve returned while the solver was still running
 }
 
+// abandonGracePeriod is how long a cancelled solver is given to return before
+// it's abandoned
+const abandonGracePeriod = 100 * time.Millisecond
+
 // HandlerOption is a functional option type for AdventHandler
 type HandlerOption func(*AdventHandler)
 
@@ -73,69 +104,295 @@ func WithSolvers(functs ...HandlerFunc) HandlerOption {
 // and parse the cmd flags
 func WithArgs(args []string) HandlerOption {
 	return func(h *AdventHandler) {
-		if h.cmd != nil && !h.cmd.Flags().Parsed() {
-			HandleErr(h.cmd.Flags().Parse(args))
+		if h.cmd != nil && !h.cmd.Flags().Parsed() && h.optErr == nil {
+			h.optErr = h.cmd.Flags().Parse(args)
 		}
 	}
 }
 
-// NewHandler creates a pointer reference to a new AdventHandler struct and
-// initializes the filestream and reader(s)
-func NewHandler(opts ...HandlerOption) (h *AdventHandler) {
-	if ActiveCmd == nil {
-		HandleErr(&ActiveCmdUndefinedError{})
+// WithPuzzle is a functional option that assigns the day, puzzle and sample
+// values directly instead of reading them from the command flags
+func WithPuzzle(day, puzzle int, isSample bool) HandlerOption {
+	return func(h *AdventHandler) {
+		h.cmd = nil
+		h.DayNum = day
+		h.PuzzleNum = puzzle
+		h.IsSample = isSample
+	}
+}
+
+// WithInput is a functional option that reads the puzzle data from the given
+// path instead of the day's data directory, "-" reads from stdin
+func WithInput(path string) HandlerOption {
+	return func(h *AdventHandler) {
+		h.InputPath = path
+	}
+}
+
+// WithReader is a functional option that reads the puzzle data from the reader
+// instead of a file, e.g. to run the solvers against in-memory data
+func WithReader(r io.Reader) HandlerOption {
+	return func(h *AdventHandler) {
+		h.inputReader = r
+	}
+}
+
+// WithContext is a functional option that assigns the context the solver runs
+// under, instead of the command's context
+func WithContext(ctx context.Context) HandlerOption {
+	return func(h *AdventHandler) {
+		h.ctx = ctx
+	}
+}
+
+// WithOutput is a functional option that assigns the writer solver output is
+// printed to
+func WithOutput(out io.Writer) HandlerOption {
+	return func(h *AdventHandler) {
+		h.Out = out
+	}
+}
+
+// WithProfileOutput is a functional option that assigns the writer the solver's
+// profiles are logged to, instead of ProfileOut
+func WithProfileOutput(out io.Writer) HandlerOption {
+	return func(h *AdventHandler) {
+		h.profileOut = out
+	}
+}
+
+// WithProgressOutput is a functional option that assigns the writer the
+// solver's progress is rendered to, instead of ProgressOut
+func WithProgressOutput(out io.Writer) HandlerOption {
+	return func(h *AdventHandler) {
+		h.progressOut = out
 	}
+}
+
+// WithExpected is a functional option that assigns the answer the solver is
+// expected to report, instead of the one in the day's answers file
+func WithExpected(answer string) HandlerOption {
+	return func(h *AdventHandler) {
+		h.Expected = answer
+	}
+}
+
+// WithRecords is a functional option that writes a record of each Solve to
+// the record writer
+func WithRecords(records *RecordWriter) HandlerOption {
+	return func(h *AdventHandler) {
+		h.records = records
+	}
+}
 
+// NewHandlerE creates a pointer reference to a new AdventHandler struct for the
+// executing command and initializes the filestream and reader(s). The command
+// may be nil if the puzzle is assigned with the WithPuzzle option. The handler
+// should be closed once solved, even if an error is returned.
+func NewHandlerE(cmd *cobra.Command, opts ...HandlerOption) (h *AdventHandler, err error) {
 	// Initialize the handler
-	h = &AdventHandler{cmd: ActiveCmd}
-	var err error
+	h = &AdventHandler{cmd: cmd, Out: os.Stdout, start: time.Now()}
 
 	// Apply the functional options
 	for _, opt := range opts {
 		opt(h)
 	}
+	if h.optErr != nil {
+		return h, h.optErr
+	}
+	if h.ctx == nil && h.cmd != nil {
+		h.ctx = h.cmd.Context()
+	}
+	if h.ctx == nil {
+		h.ctx = context.Background()
+	}
 
 	// Assign/derive values from the command flags
-	if h.cmd.Flags().Parsed() {
-		h.DayNum = GetFlagIntD("day-num", 1)
+	if h.cmd != nil {
+		if !h.cmd.Flags().Parsed() {
+			return h, nil
+		}
 
-		h.PuzzleNum = GetFlagIntD("puzzle-num", 1)
+		if h.DayNum, err = CmdDay(h.cmd); err != nil {
+			return h, err
+		}
 
-		h.Day = strconv.Itoa(h.DayNum)
+		h.PuzzleNum = GetFlagIntD(h.cmd, "puzzle-num", 1)
 
-		h.Puzzle = strconv.Itoa(h.PuzzleNum)
+		h.IsSample = GetFlagBool(h.cmd, "sample")
 
-		h.IsSample = GetFlagBool("sample")
+		// Only an explicit flag overrides the WithInput and WithExpected options
+		if h.cmd.Flags().Changed("input") {
+			h.InputPath = GetFlagString(h.cmd, "input")
+		}
 
-		h.debugEnabled = GetFlagBool("debug")
+		if h.cmd.Flags().Changed("expect") {
+			h.Expected = GetFlagString(h.cmd, "expect")
+		}
 
-		if err = h.getPuzzleDataScanner(); err != nil {
-			HandleErr(err)
+		// The records replace the solver's free-form output
+		if format := GetFlagStringD(h.cmd, "format", FormatText); format != FormatText && h.records == nil {
+			if h.records, err = NewRecordWriter(os.Stdout, format); err != nil {
+				return h, err
+			}
+			h.Out = io.Discard
 		}
 	}
-	return h
-}
 
-func (h *AdventHandler) Debug(args ...any) {
-	if h.debugEnabled {
-		fmt.Println(args...)
+	h.Day = strconv.Itoa(h.DayNum)
+
+	h.Puzzle = strconv.Itoa(h.PuzzleNum)
+
+	h.Log = slog.Default().With(ComponentKey, "day"+h.Day)
+
+	// Fall back to the solvers registered for the day
+	if h.solvers == nil {
+		if puzzle, ok := GetDay(h.DayNum); ok {
+			h.solvers = puzzle.Solvers
+		}
 	}
+
+	// The answers file only applies to the day's own data
+	if h.Expected == "" && h.InputPath == "" && h.inputReader == nil {
+		answers, err := LoadAnswers(h.DayNum)
+		if err != nil {
+			return h, err
+		}
+		h.Expected, _ = answers.Get(h.PuzzleNum, h.IsSample)
+	}
+
+	if err = h.getPuzzleDataScanner(); err != nil {
+		return h, err
+	}
+	return h, nil
 }
 
-func (h *AdventHandler) Debugf(fmtStr string, args ...any) {
-	if h.debugEnabled {
-		fmt.Printf(fmtStr, args...)
+// Cmd returns the command the handler was created for, nil if the puzzle was
+// assigned directly
+func (h *AdventHandler) Cmd() *cobra.Command {
+	return h.cmd
+}
+
+// ComponentLog returns a logger for a component of the day's solvers, named
+// e.g. day2.report so that it can be filtered separately from the day
+func (h *AdventHandler) ComponentLog(name string) *slog.Logger {
+	return slog.Default().With(ComponentKey, "day"+h.Day+"."+name)
+}
+
+// StartSpan begins a profiling span nested inside of the puzzle's span (e.g.
+// day1/puzzle1/parse), the returned function ends it
+func (h *AdventHandler) StartSpan(name string) func() *SpanStats {
+	return h.spans.Start(name)
+}
+
+// StartProfile returns a function that logs the time it took to execute a
+// task with the given name to the handler's profile output, see StartProfile
+func (h *AdventHandler) StartProfile(taskName string) func() time.Duration {
+	out := h.profileOut
+	if out == nil {
+		out = ProfileOut
 	}
+	stop := startSpan(taskName)
+
+	return func() time.Duration {
+		span := stop()
+		writeProfile(out, span)
+		return span.Elapsed
+	}
+}
+
+// Println prints the args to the handler's output
+func (h *AdventHandler) Println(args ...any) {
+	fmt.Fprintln(h.Out, args...)
 }
 
-// Close closes the filestream
+// Printf prints the formatted string to the handler's output
+func (h *AdventHandler) Printf(fmtStr string, args ...any) {
+	fmt.Fprintf(h.Out, fmtStr, args...)
+}
+
+// Report records the answer to the puzzle and prints it with the given label
+func (h *AdventHandler) Report(label string, answer any) {
+	h.Answer = fmt.Sprint(answer)
+	h.Println(label, answer)
+}
+
+// Close closes the filestream. If the solver was abandoned it still owns the
+// input, so the input is closed once the solver returns instead
 func (h *AdventHandler) Close() {
-	h.FileStream.Close()
+	if h.abandoned {
+		return
+	}
+	h.closeInput()
 }
 
-// Scan is a shortcut to the Scanner's Scan method
+// closeInput closes the decompression reader and filestream, unless it's stdin
+func (h *AdventHandler) closeInput() {
+	if h.inputCloser != nil {
+		h.inputCloser.Close()
+	}
+	if h.FileStream != nil && h.FileStream != os.Stdin {
+		h.FileStream.Close()
+	}
+}
+
+// Scan is a shortcut to the Scanner's Scan method that also counts the lines read
 func (h *AdventHandler) Scan() bool {
-	return h.Scanner.Scan()
+	if !h.Scanner.Scan() {
+		return false
+	}
+	h.lineNum.Add(1)
+	return true
+}
+
+// LineNum returns the 1-based number of the last line read with Scan, it's safe
+// to call while the solver is running
+func (h *AdventHandler) LineNum() int {
+	return int(h.lineNum.Load())
+}
+
+// Context returns the context the solver runs under, long running solvers
+// should stop once it's done
+func (h *AdventHandler) Context() context.Context {
+	return h.ctx
+}
+
+// Err returns the context's error, a shortcut for solver loops to check for
+// cancellation
+func (h *AdventHandler) Err() error {
+	return h.ctx.Err()
+}
+
+// DataError wraps the error as an InvalidDataError for the last line read with
+// Scan. If the error already is one, a copy is returned with the line, line
+// number and input path filled in where they're missing.
+func (h *AdventHandler) DataError(err error) *InvalidDataError {
+	return h.DataErrorAt(err, h.Text(), h.LineNum())
+}
+
+// DataErrorAt wraps the error as an InvalidDataError for the given line, like
+// DataError
+func (h *AdventHandler) DataErrorAt(err error, line string, lineNum int) *InvalidDataError {
+	dataErr := &InvalidDataError{Err: err}
+	var target *InvalidDataError
+	if errors.As(err, &target) {
+		copied := *target
+		dataErr = &copied
+	}
+
+	if dataErr.Line == "" {
+		dataErr.Line = line
+	}
+	if dataErr.LineNum == 0 {
+		dataErr.LineNum = lineNum
+	}
+	if dataErr.Path == "" {
+		dataErr.Path = h.InputPath
+		if h.InputPath == StdinPath {
+			dataErr.Path = "<stdin>"
+		}
+	}
+	return dataErr
 }
 
 // Text is a shortcut to the Scanner's Text method
@@ -144,29 +401,201 @@ func (h *AdventHandler) Text() string {
 }
 
 // Solve Checks the command's puzzle num and if there's been a solver function
-// assigned to that puzzle and executes it if so
+// assigned to that puzzle and executes it if so. An AnswerMismatchError is
+// returned if the answer differs from the expected answer. If the handler has
+// a record writer, a record of the run is written to it
 func (h *AdventHandler) Solve() error {
+	return h.WriteRecord(h.solve())
+}
+
+// WriteRecord writes a record of the run that ended with err, if the handler
+// has a record writer. It returns err, joined with any error writing the record,
+// so that runs that fail before solving (e.g. missing input) are recorded too
+func (h *AdventHandler) WriteRecord(err error) error {
+	if h.records == nil {
+		return err
+	}
+	if writeErr := h.records.Write(h.Record(err)); writeErr != nil {
+		return errors.Join(err, writeErr)
+	}
+	return err
+}
+
+// Record describes the last Solve, which returned err. The input hash covers
+// all of the input, any data the solver didn't read is read to finish it. The
+// answer is left empty if the solver was abandoned, as it may still be running,
+// and the input hash is left empty if the run was cancelled, as the rest of the
+// input may never arrive
+func (h *AdventHandler) Record(err error) *RunRecord {
+	record := &RunRecord{Day: h.DayNum, Part: h.PuzzleNum, Sample: h.IsSample}
+
+	if !h.abandoned {
+		record.Answer = h.Answer
+	}
+	if !h.abandoned && h.ctx.Err() == nil {
+		if h.inputTee != nil {
+			io.Copy(io.Discard, h.inputTee)
+			record.InputHash = hex.EncodeToString(h.inputHash.Sum(nil))
+		}
+	}
+	if h.stats != nil {
+		record.Elapsed = h.stats.Elapsed
+		record.Allocs = h.stats.Allocs
+		record.AllocBytes = h.stats.AllocBytes
+	}
+	if err != nil {
+		record.Error = strings.TrimSpace(err.Error())
+	}
+	return record
+}
+
+// solve runs the solver for the puzzle, abandoning it if the context is done
+func (h *AdventHandler) solve() error {
 	// use puzzle number -1 since the puzzle nums are not 0-based
 	solverIdx := h.PuzzleNum - 1
-	if solverIdx <= len(h.solvers) {
-		return h.solvers[solverIdx](h)
+	if solverIdx < 0 || solverIdx >= len(h.solvers) || h.solvers[solverIdx] == nil {
+		return &SolverUndefinedError{DayNum: h.DayNum, PuzzleNum: h.PuzzleNum}
+	}
+
+	stop := h.StartSpan("day" + h.Day + "/puzzle" + h.Puzzle)
+	defer func() { h.stats = stop() }()
+
+	// The solver runs in its own goroutine so that a solver that doesn't check
+	// the context can still be abandoned when the run is cancelled
+	if h.ctx.Err() != nil {
+		return h.cancelledError()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- h.solvers[solverIdx](h)
+	}()
+
+	select {
+	case err := <-done:
+		return h.checkAnswer(err)
+	case <-h.ctx.Done():
+	}
+
+	// Give the solver a moment to return, e.g. if it checks the context
+	select {
+	case err := <-done:
+		return h.checkAnswer(err)
+	case <-time.After(abandonGracePeriod):
+	}
+
+	// The solver may still be reading the input, so it's left to close it
+	h.abandoned = true
+	go func() {
+		<-done
+		h.closeInput()
+	}()
+	return h.cancelledError()
+}
+
+// checkAnswer compares the reported answer against the expected answer, once
+// the solver has finished without an error
+func (h *AdventHandler) checkAnswer(err error) error {
+	if err != nil || h.Expected == "" || h.Answer == h.Expected {
+		return err
+	}
+	return &AnswerMismatchError{
+		DayNum:    h.DayNum,
+		PuzzleNum: h.PuzzleNum,
+		IsSample:  h.IsSample,
+		Expected:  h.Expected,
+		Answer:    h.Answer,
+	}
+}
+
+// cancelledError describes the progress of a run cancelled by the context
+func (h *AdventHandler) cancelledError() *CancelledError {
+	return &CancelledError{
+		DayNum:    h.DayNum,
+		PuzzleNum: h.PuzzleNum,
+		Elapsed:   time.Since(h.start),
+		LinesRead: h.LineNum(),
+		Err:       context.Cause(h.ctx),
 	}
-	return &SolverUndefinedError{PuzzleNum: h.PuzzleNum}
 }
 
-// getPuzzleDataScanner assigns a filestream and scanner for the puzzle data
+// getPuzzleDataScanner assigns a filestream and scanner for the puzzle data,
+// gzip compressed data is decompressed automatically
 func (h *AdventHandler) getPuzzleDataScanner() (err error) {
-	fileName := "puzzle" + h.Puzzle + ".txt"
-	if h.IsSample {
-		fileName = "sample" + h.Puzzle + ".txt"
+	if h.inputReader != nil {
+		h.inputHash = sha256.New()
+		h.inputTee = io.TeeReader(h.inputReader, h.inputHash)
+		h.Scanner = bufio.NewScanner(h.inputTee)
+		return nil
 	}
-	path := filepath.Join(DataDir, "day"+h.Day, fileName)
-	h.FileStream, err = os.Open(path)
-	if err != nil {
-		return err
+
+	if h.InputPath == "" {
+		h.InputPath = ResolveInputPath(h.DayNum, h.PuzzleNum, h.IsSample)
 	}
 
-	h.Scanner = bufio.NewScanner(h.FileStream)
+	// Opening a FIFO or sniffing the header of stdin blocks until data arrives,
+	// so it's abandoned if the run is cancelled first
+	opened := make(chan *openedInput, 1)
+	go func() {
+		opened <- openInput(h.InputPath)
+	}()
+
+	var input *openedInput
+	select {
+	case input = <-opened:
+	case <-h.ctx.Done():
+		select {
+		case input = <-opened:
+		default:
+			go func() { (<-opened).close() }()
+			return h.cancelledError()
+		}
+	}
+
+	if errors.Is(input.err, os.ErrNotExist) {
+		return &InputNotFoundError{Path: h.InputPath, Err: input.err}
+	} else if input.err != nil {
+		return input.err
+	}
+	h.FileStream, h.inputCloser = input.file, input.closer
+
+	h.inputHash = sha256.New()
+	h.inputTee = io.TeeReader(input.reader, h.inputHash)
+	h.Scanner = bufio.NewScanner(h.inputTee)
 
 	return nil
 }
+
+// openedInput is the result of opening the puzzle data
+type openedInput struct {
+	stdin  bool // stdin is set if the data is read from stdin, which isn't closed
+	file   *os.File
+	reader io.Reader
+	closer io.Closer
+	err    error
+}
+
+// openInput opens the puzzle data at the path and wraps it with a decompressing
+// reader if needed
+func openInput(path string) *openedInput {
+	file, err := OpenInput(path)
+	if err != nil {
+		return &openedInput{err: err}
+	}
+
+	reader, closer, err := NewInputReader(file)
+	input := &openedInput{stdin: path == StdinPath, file: file, reader: reader, closer: closer, err: err}
+	if err != nil {
+		input.close()
+	}
+	return input
+}
+
+// close closes the opened input, unless it's stdin
+func (input *openedInput) close() {
+	if input.closer != nil {
+		input.closer.Close()
+	}
+	if input.file != nil && !input.stdin {
+		input.file.Close()
+	}
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: handler_test
+	Description: Tests for running solvers through the AdventHandler
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func solveHandler(t *testing.T, ctx context.Context, solver HandlerFunc) *AdventHandler {
+	t.Helper()
+	handler, err := NewHandlerE(nil,
+		WithPuzzle(1, 1, false),
+		WithReader(strings.NewReader("1\n2\n3\n")),
+		WithOutput(io.Discard),
+		WithContext(ctx),
+		WithSolvers(solver),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(handler.Close)
+	return handler
+}
+
+func TestSolveTimeoutAbandonsSolver(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The solver ignores the context and would block until the test ends
+	release := make(chan struct{})
+	defer close(release)
+	handler := solveHandler(t, ctx, func(h *AdventHandler) error {
+		h.Scan()
+		h.Scan()
+		<-release
+		return nil
+	})
+
+	err := handler.Solve()
+	var cancelled *CancelledError
+	if !errors.As(err, &cancelled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a CancelledError for the deadline, got %v", err)
+	}
+	if cancelled.LinesRead != 2 || cancelled.Elapsed < 50*time.Millisecond {
+		t.Errorf("unexpected progress: %+v", cancelled)
+	}
+}
+
+func TestSolveAbandonedSolverOwnsInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("1\n2\n3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	handler, err := NewHandlerE(nil,
+		WithPuzzle(1, 1, false),
+		WithInput(path),
+		WithOutput(io.Discard),
+		WithContext(ctx),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The solver keeps scanning after it's abandoned and Close is called
+	release := make(chan struct{})
+	returned := make(chan int)
+	WithSolvers(func(h *AdventHandler) error {
+		cancel()
+		<-release
+		lines := 0
+		for h.Scan() {
+			lines++
+		}
+		h.Report("Lines:", lines)
+		returned <- lines
+		return nil
+	})(handler)
+
+	err = handler.Solve()
+	record := handler.Record(err)
+	handler.Close()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if record.Answer != "" || record.InputHash != "" {
+		t.Errorf("expected no answer or input hash, got %+v", record)
+	}
+
+	close(release)
+	if lines := <-returned; lines != 3 {
+		t.Errorf("expected the abandoned solver to read 3 lines, got %d", lines)
+	}
+	closed := func() bool {
+		_, err := handler.FileStream.Read(make([]byte, 1))
+		return errors.Is(err, os.ErrClosed)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !closed() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the input to be closed once the solver returned")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSolveCooperativeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := solveHandler(t, ctx, func(h *AdventHandler) error {
+		cancel()
+		<-h.Context().Done()
+		return h.Err()
+	})
+
+	if err := handler.Solve(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSolveAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ran := false
+	handler := solveHandler(t, ctx, func(h *AdventHandler) error {
+		ran = true
+		return nil
+	})
+	if err := handler.Solve(); !errors.Is(err, context.Canceled) || ran {
+		t.Fatalf("expected the solver not to run, got %v (ran: %v)", err, ran)
+	}
+}
+
+func TestSolveExpectedAnswer(t *testing.T) {
+	countLines := func(h *AdventHandler) error {
+		lines := 0
+		for h.Scan() {
+			lines++
+		}
+		h.Report("Lines:", lines)
+		return nil
+	}
+
+	handler := solveHandler(t, context.Background(), countLines)
+	WithExpected("4")(handler)
+	var mismatch *AnswerMismatchError
+	if err := handler.Solve(); !errors.As(err, &mismatch) {
+		t.Fatalf("expected an AnswerMismatchError, got %v", err)
+	}
+	if !strings.Contains(mismatch.Error(), "- expected: 4\n+ answer:   3 (-1)") {
+		t.Errorf("expected a diff of the answers, got:\n%s", mismatch.Error())
+	}
+
+	handler = solveHandler(t, context.Background(), countLines)
+	WithExpected("3")(handler)
+	if err := handler.Solve(); err != nil {
+		t.Errorf("expected the answer to match, got %v", err)
+	}
+}
+
+func TestSolveAnswersFile(t *testing.T) {
+	dataDir := DataDir
+	DataDir = t.TempDir()
+	defer func() { DataDir = dataDir }()
+
+	dayDir := DayDir(1)
+	os.MkdirAll(dayDir, 0o755)
+	os.WriteFile(filepath.Join(dayDir, InputFileName), []byte("a\nb\n"), 0o644)
+	os.WriteFile(filepath.Join(dayDir, AnswersFile), []byte("puzzle1: 3\n"), 0o644)
+
+	handler, err := NewHandlerE(nil,
+		WithPuzzle(1, 1, false),
+		WithOutput(io.Discard),
+		WithSolvers(func(h *AdventHandler) error {
+			h.Report("Answer:", 2)
+			return nil
+		}),
+	)
+	defer handler.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handler.Expected != "3" {
+		t.Fatalf("expected the answer from the answers file, got %q", handler.Expected)
+	}
+	if err = handler.Solve(); ExitCode(err) != ExitWrongAnswer {
+		t.Errorf("expected a wrong answer, got %v", err)
+	}
+}
+
+func TestSolveUndefinedPuzzle(t *testing.T) {
+	solver := func(h *AdventHandler) error { return nil }
+	tests := map[string]struct {
+		puzzleNum int
+		solvers   []HandlerFunc
+	}{
+		"puzzle 0":        {0, []HandlerFunc{solver, solver}},
+		"past the end":    {3, []HandlerFunc{solver, solver}},
+		"nil solver slot": {2, []HandlerFunc{solver, nil}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler, err := NewHandlerE(nil,
+				WithPuzzle(1, test.puzzleNum, false),
+				WithReader(strings.NewReader("")),
+				WithSolvers(test.solvers...),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var undefined *SolverUndefinedError
+			if err = handler.Solve(); !errors.As(err, &undefined) || undefined.PuzzleNum != test.puzzleNum {
+				t.Errorf("expected a SolverUndefinedError, got %v", err)
+			}
+
+			day := &DailyPuzzle{DayNum: 1, Solvers: test.solvers}
+			if day.HasSolver(test.puzzleNum) {
+				t.Errorf("expected no solver for puzzle %d", test.puzzleNum)
+			}
+		})
+	}
+}
+
+func TestNewHandlerFlagsOverrideOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringP("input", "f", "", "")
+		cmd.Flags().StringP("expect", "e", "", "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"options kept without flags", nil, "3"},
+		{"explicit flag wins", []string{"--expect", "4"}, "4"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, err := NewHandlerE(newCmd(test.args...),
+				WithInput(path),
+				WithExpected("3"),
+				WithOutput(io.Discard),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer handler.Close()
+			if handler.InputPath != path || handler.Expected != test.expected {
+				t.Errorf("input = %q, expected = %q, want %q and %q", handler.InputPath, handler.Expected, path, test.expected)
+			}
+		})
+	}
+}
+
+func TestCmdDay(t *testing.T) {
+	newCmd := func(day int, args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		if day > 0 {
+			InitDailyCmd(cmd, day)
+			t.Cleanup(func() { delete(registry, day) })
+		} else {
+			cmd.Flags().IntP("day-num", "d", 1, "")
+		}
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	tests := []struct {
+		name       string
+		cmd        *cobra.Command
+		expected   int
+		mismatched bool
+	}{
+		{"registered day", newCmd(99), 99, false},
+		{"matching flag", newCmd(99, "-d", "99"), 99, false},
+		{"mismatched flag", newCmd(99, "-d", "3"), 0, true},
+		{"unregistered command", newCmd(0, "-d", "3"), 3, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			day, err := CmdDay(test.cmd)
+			if (err != nil) != test.mismatched || day != test.expected {
+				t.Errorf("got day %d and %v, want day %d (mismatched: %v)", day, err, test.expected, test.mismatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+//go:build unix
+
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: handler_unix_test
+	Description: Tests for cancelling the handler while the input blocks
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNewHandlerCancelledWhileOpening(t *testing.T) {
+	// Opening a FIFO blocks until there's a writer
+	path := filepath.Join(t.TempDir(), "input.fifo")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skip("can't create a FIFO:", err)
+	}
+	defer func() {
+		// Unblock the abandoned open so it can clean up
+		if writer, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			writer.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	handler, err := NewHandlerE(nil, WithPuzzle(1, 1, false), WithInput(path), WithContext(ctx))
+	defer handler.Close()
+
+	var cancelled *CancelledError
+	if !errors.As(err, &cancelled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a CancelledError for the deadline, got %v", err)
+	}
+	if cancelled.Elapsed < 50*time.Millisecond {
+		t.Errorf("expected the elapsed time to cover opening the input, got %v", cancelled.Elapsed)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: input
+	Description: Code for opening the puzzle input from files, stdin or gzip archives
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	StdinPath        = "-"          // StdinPath is the input path used to read the puzzle data from stdin
+	InputFileName    = "input.txt"  // InputFileName is the day's puzzle data shared by all parts
+	SampleFileName   = "sample.txt" // SampleFileName is the day's sample data shared by all parts
+	puzzleFilePrefix = "puzzle"     // puzzleFilePrefix is the prefix of the part specific puzzle data
+	sampleFilePrefix = "sample"     // sampleFilePrefix is the prefix of the part specific sample data
+)
+
+// gzipMagic is the header that identifies gzip compressed data
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// OpenInput opens the file at the given path, or stdin if the path is "-"
+func OpenInput(path string) (*os.File, error) {
+	if path == StdinPath {
+		return os.Stdin, nil
+	}
+	return os.Open(path)
+}
+
+// NewInputReader wraps the reader so that gzip compressed data is decompressed
+// transparently. The returned closer must be closed once reading is finished,
+// it does not close the underlying reader
+func NewInputReader(r io.Reader) (reader io.Reader, closer io.Closer, err error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+
+	if !bytes.Equal(header, gzipMagic) {
+		return buffered, io.NopCloser(nil), nil
+	}
+
+	gzReader, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gzReader, gzReader, nil
+}
+
+// ResolveInputPath finds the data file for the day and puzzle. The day's shared
+// input.txt or sample.txt is preferred, the part specific files (e.g.
+// puzzle2.txt) are only used when they have content that differs from the
+// other part's file, or when there is no shared file. Both parts share the
+// same puzzle input, so a warning is printed if both part specific puzzle files
+// have diverging content. Samples often differ between parts, so that's only
+// logged at debug level
+func ResolveInputPath(day, puzzleNum int, isSample bool) string {
+	dayDir := DayDir(day)
+
+	sharedPath := filepath.Join(dayDir, InputFileName)
+	prefix := puzzleFilePrefix
+	if isSample {
+		sharedPath = filepath.Join(dayDir, SampleFileName)
+		prefix = sampleFilePrefix
+	}
+
+	otherNum := 1
+	if puzzleNum == 1 {
+		otherNum = 2
+	}
+	partPath := filepath.Join(dayDir, prefix+strconv.Itoa(puzzleNum)+".txt")
+	otherPath := filepath.Join(dayDir, prefix+strconv.Itoa(otherNum)+".txt")
+
+	partData := readNonEmpty(partPath)
+	otherData := readNonEmpty(otherPath)
+
+	if partData != nil && otherData != nil && !bytes.Equal(partData, otherData) {
+		level := slog.LevelWarn
+		if isSample {
+			level = slog.LevelDebug
+		}
+		slog.Log(
+			context.Background(),
+			level,
+			"part specific data files have diverging content",
+			"using", partPath,
+			"other", otherPath,
+		)
+		return partPath
+	}
+
+	if fileExists(sharedPath) {
+		return sharedPath
+	}
+
+	// Legacy layout without a shared file, either part's data can be used
+	switch {
+	case partData != nil:
+		return partPath
+	case otherData != nil:
+		return otherPath
+	case fileExists(partPath):
+		return partPath
+	}
+
+	// Nothing exists, so the error when opening refers to the preferred file
+	return sharedPath
+}
+
+// readNonEmpty reads the file, returning nil if it doesn't exist or is empty
+func readNonEmpty(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
+// fileExists checks if a regular file exists at the path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: input_test
+	Description: Tests for opening the puzzle input and resolving its path
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readInput reads all of the data through NewInputReader
+func readInput(t *testing.T, r io.Reader) string {
+	t.Helper()
+	reader, closer, err := NewInputReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestOpenInputStdin(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = stdin }()
+
+	file, err := OpenInput(StdinPath)
+	if err != nil || file != reader {
+		t.Fatalf("expected stdin, got %v, %v", file, err)
+	}
+	writer.WriteString("1 2\n3 4\n")
+	writer.Close()
+	if data := readInput(t, file); data != "1 2\n3 4\n" {
+		t.Errorf("read %q from stdin", data)
+	}
+
+	if _, err = OpenInput("does/not/exist.txt"); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
+
+func TestNewInputReaderGzip(t *testing.T) {
+	var compressed bytes.Buffer
+	gzWriter := gzip.NewWriter(&compressed)
+	gzWriter.Write([]byte("compressed\ndata\n"))
+	gzWriter.Close()
+
+	if data := readInput(t, &compressed); data != "compressed\ndata\n" {
+		t.Errorf("expected the data to be decompressed, got %q", data)
+	}
+
+	// Only the magic bytes identify gzip data, not the first one alone
+	if data := readInput(t, bytes.NewReader([]byte{0x1f, 'a'})); data != "\x1fa" {
+		t.Errorf("expected plain data, got %q", data)
+	}
+	if _, _, err := NewInputReader(bytes.NewReader([]byte{0x1f, 0x8b, 0})); err == nil {
+		t.Error("expected an error for a truncated gzip header")
+	}
+}
+
+func TestNewInputReaderShortInput(t *testing.T) {
+	for _, input := range []string{"", "x", "\x1f"} {
+		if data := readInput(t, bytes.NewReader([]byte(input))); data != input {
+			t.Errorf("read %q, want %q", data, input)
+		}
+	}
+}
+
+func TestResolveInputPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string // files are the day's data files and their content
+		puzzleNum int
+		isSample  bool
+		expected  string
+		level     string // level is the level of the diverging content log, if any
+	}{
+		{"shared only", map[string]string{"input.txt": "a"}, 2, false, "input.txt", ""},
+		{"shared over identical parts", map[string]string{"input.txt": "a", "puzzle1.txt": "b", "puzzle2.txt": "b"}, 1, false, "input.txt", ""},
+		{"shared over one part", map[string]string{"input.txt": "a", "puzzle2.txt": "b"}, 2, false, "input.txt", ""},
+		{"diverging parts", map[string]string{"input.txt": "a", "puzzle1.txt": "b", "puzzle2.txt": "c"}, 2, false, "puzzle2.txt", "WARN"},
+		{"diverging samples", map[string]string{"sample1.txt": "b", "sample2.txt": "c"}, 1, true, "sample1.txt", "DEBUG"},
+		{"legacy part", map[string]string{"puzzle1.txt": "b"}, 1, false, "puzzle1.txt", ""},
+		{"legacy other part", map[string]string{"sample1.txt": "b", "sample2.txt": ""}, 2, true, "sample1.txt", ""},
+		{"legacy empty part", map[string]string{"puzzle2.txt": ""}, 2, false, "puzzle2.txt", ""},
+		{"nothing", map[string]string{}, 1, true, "sample.txt", ""},
+	}
+
+	defer slog.SetDefault(slog.Default())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useTempDataDir(t)
+			dayDir := DayDir(1)
+			os.MkdirAll(dayDir, 0o755)
+			for name, content := range test.files {
+				if err := os.WriteFile(filepath.Join(dayDir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var logs bytes.Buffer
+			slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+			path := ResolveInputPath(1, test.puzzleNum, test.isSample)
+			if path != filepath.Join(dayDir, test.expected) {
+				t.Errorf("path = %s, want %s", path, test.expected)
+			}
+			logged := strings.Contains(logs.String(), "diverging content")
+			if logged != (test.level != "") || (logged && !strings.Contains(logs.String(), "level="+test.level)) {
+				t.Errorf("expected a %q diverging content log, got: %s", test.level, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: log
+	Description: Leveled, filterable logging for the solvers and commands
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	LevelTrace   = slog.Level(-8) // LevelTrace is for very verbose output, below debug
+	ComponentKey = "component"    // ComponentKey is the log attribute that names the logging component
+)
+
+// LogConfig holds the settings for the logger
+type LogConfig struct {
+	Level      slog.Level // Level is the minimum level that's logged
+	JSON       bool       // JSON determines if the output is JSON instead of text
+	Components []string   // Components limits debug and trace logs to these components and their children
+}
+
+// ParseLogLevel converts a level name (trace, debug, info, warn or error) into
+// its slog level
+func ParseLogLevel(name string) (slog.Level, error) {
+	if strings.EqualFold(name, "trace") {
+		return LevelTrace, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("invalid log level %q, expected trace, debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// NewLogger creates a logger that writes to the writer using the config
+func NewLogger(w io.Writer, config LogConfig) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level: config.Level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.LevelKey && attr.Value.Any() == LevelTrace {
+				attr.Value = slog.StringValue("TRACE")
+			}
+			return attr
+		},
+	}
+
+	var handler slog.Handler = slog.NewTextHandler(w, opts)
+	if config.JSON {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	if len(config.Components) > 0 {
+		handler = &componentFilter{Handler: handler, components: config.Components}
+	}
+	return slog.New(handler)
+}
+
+// Trace logs the message at the trace level, using the default logger if the
+// logger is nil
+func Trace(logger *slog.Logger, msg string, args ...any) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Log(context.Background(), LevelTrace, msg, args...)
+}
+
+// componentFilter is a slog.Handler that drops debug and trace records from
+// components that aren't in the list of components. A component matches if it
+// is in the list or is a child of one (e.g. day2.report matches day2)
+type componentFilter struct {
+	slog.Handler
+	components []string
+	component  string
+}
+
+// Enabled reports if the handler handles records at the level for the component
+func (f *componentFilter) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < slog.LevelInfo && !f.allowed() {
+		return false
+	}
+	return f.Handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new filter with the attributes, tracking the component
+func (f *componentFilter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	component := f.component
+	for _, attr := range attrs {
+		if attr.Key == ComponentKey {
+			component = attr.Value.String()
+		}
+	}
+	return &componentFilter{Handler: f.Handler.WithAttrs(attrs), components: f.components, component: component}
+}
+
+// WithGroup returns a new filter with the group
+func (f *componentFilter) WithGroup(name string) slog.Handler {
+	return &componentFilter{Handler: f.Handler.WithGroup(name), components: f.components, component: f.component}
+}
+
+// allowed checks if the filter's component is in the list of components
+func (f *componentFilter) allowed() bool {
+	for _, component := range f.components {
+		if f.component == component || strings.HasPrefix(f.component, component+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: log_test
+	Description: Tests for the leveled, component filtered logger
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"trace": LevelTrace,
+		"TRACE": LevelTrace,
+		"debug": slog.LevelDebug,
+		"info":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+	for name, expected := range tests {
+		level, err := ParseLogLevel(name)
+		if err != nil || level != expected {
+			t.Errorf("ParseLogLevel(%q) = %v, %v, want %v", name, level, err, expected)
+		}
+	}
+
+	if _, err := ParseLogLevel("loud"); err == nil || !strings.Contains(err.Error(), "loud") {
+		t.Errorf("expected an invalid level error, got %v", err)
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, LogConfig{Level: LevelTrace})
+	Trace(logger, "tracing", "n", 1)
+	if !strings.Contains(out.String(), "level=TRACE msg=tracing n=1") {
+		t.Errorf("expected a trace record, got %q", out.String())
+	}
+
+	out.Reset()
+	logger = NewLogger(&out, LogConfig{Level: slog.LevelInfo, JSON: true})
+	logger.Debug("hidden")
+	logger.Info("shown")
+	if strings.Contains(out.String(), "hidden") || !strings.Contains(out.String(), `"msg":"shown"`) {
+		t.Errorf("expected only the info record as JSON, got %q", out.String())
+	}
+}
+
+func TestComponentFilter(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, LogConfig{Level: slog.LevelDebug, Components: []string{"day2"}})
+
+	logger.With(ComponentKey, "day2").Debug("day")
+	logger.With(ComponentKey, "day2.report").Debug("child")
+	logger.With(ComponentKey, "day20").Debug("prefix")
+	logger.With(ComponentKey, "day5").Debug("other")
+	logger.With(ComponentKey, "day5").Info("info")
+
+	for _, msg := range []string{"msg=day", "msg=child", "msg=info"} {
+		if !strings.Contains(out.String(), msg) {
+			t.Errorf("expected %s to be logged, got:\n%s", msg, out.String())
+		}
+	}
+	for _, msg := range []string{"msg=prefix", "msg=other"} {
+		if strings.Contains(out.String(), msg) {
+			t.Errorf("expected %s to be filtered, got:\n%s", msg, out.String())
+		}
+	}
+}
+
+func TestTraceNilLogger(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	var out bytes.Buffer
+	slog.SetDefault(NewLogger(&out, LogConfig{Level: LevelTrace}))
+
+	Trace(nil, "fallback")
+	if !strings.Contains(out.String(), "msg=fallback") {
+		t.Errorf("expected the default logger to be used, got %q", out.String())
+	}
+}
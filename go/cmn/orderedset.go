@@ -0,0 +1,112 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: orderedset
+	Description: Set that remembers the insertion order of its elements
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"iter"
+	"slices"
+)
+
+// OrderedSet is a set that iterates in insertion order, re-adding an element
+// keeps its original position. It isn't safe for concurrent use.
+type OrderedSet[T comparable] struct {
+	index    map[T]int
+	elements []T
+}
+
+// NewOrderedSet creates and returns a new OrderedSet
+func NewOrderedSet[T comparable](items ...T) *OrderedSet[T] {
+	set := &OrderedSet[T]{index: make(map[T]int, len(items))}
+	set.Add(items...)
+	return set
+}
+
+// Add appends elements that aren't already in the set
+func (s *OrderedSet[T]) Add(elements ...T) {
+	for _, element := range elements {
+		if _, exists := s.index[element]; !exists {
+			s.index[element] = len(s.elements)
+			s.elements = append(s.elements, element)
+		}
+	}
+}
+
+// Remove deletes an element from the set, shifting the later elements down
+func (s *OrderedSet[T]) Remove(element T) {
+	idx, exists := s.index[element]
+	if !exists {
+		return
+	}
+	delete(s.index, element)
+	s.elements = slices.Delete(s.elements, idx, idx+1)
+	for i := idx; i < len(s.elements); i++ {
+		s.index[s.elements[i]] = i
+	}
+}
+
+// Contains checks if an element is in the set
+func (s *OrderedSet[T]) Contains(element T) bool {
+	_, exists := s.index[element]
+	return exists
+}
+
+// Index returns the position of the element, or -1 if it isn't in the set
+func (s *OrderedSet[T]) Index(element T) int {
+	if idx, exists := s.index[element]; exists {
+		return idx
+	}
+	return -1
+}
+
+// Len returns the number of elements in the set
+func (s *OrderedSet[T]) Len() int {
+	return len(s.elements)
+}
+
+// All iterates over the elements in insertion order
+func (s *OrderedSet[T]) All() iter.Seq[T] {
+	return slices.Values(s.elements)
+}
+
+// Values returns the elements in insertion order
+func (s *OrderedSet[T]) Values() []T {
+	return slices.Clone(s.elements)
+}
+
+// Clone returns a copy of the set
+func (s *OrderedSet[T]) Clone() *OrderedSet[T] {
+	return NewOrderedSet(s.elements...)
+}
+
+// Plain returns the elements as an unordered PlainSet
+func (s *OrderedSet[T]) Plain() PlainSet[T] {
+	return NewPlainSet(s.elements...)
+}
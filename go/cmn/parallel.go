@@ -0,0 +1,199 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: parallel
+	Description: Parallel map-reduce over the lines of a handler's input
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// LineMapper maps a single line of input to a result
+type LineMapper[R any] func(ctx context.Context, line string) (R, error)
+
+// ParallelOption is a functional option for the parallel line processors
+type ParallelOption func(*parallelConfig)
+
+type parallelConfig struct {
+	workers  int
+	ordered  bool
+	progress *Progress
+}
+
+// WithWorkers sets the number of lines mapped concurrently, defaults to
+// GOMAXPROCS
+func WithWorkers(n int) ParallelOption {
+	return func(c *parallelConfig) {
+		if n > 0 {
+			c.workers = n
+		}
+	}
+}
+
+// WithOrdered reduces the results in the order of the input lines instead of
+// the order the mappers finish in
+func WithOrdered() ParallelOption {
+	return func(c *parallelConfig) {
+		c.ordered = true
+	}
+}
+
+// WithProgress advances the progress by a step for each line mapped
+func WithProgress(progress *Progress) ParallelOption {
+	return func(c *parallelConfig) {
+		c.progress = progress
+	}
+}
+
+type lineJob struct {
+	idx  int
+	line string
+}
+
+type lineResult[R any] struct {
+	idx   int
+	value R
+}
+
+// MapReduceLines maps each of the handler's remaining input lines with a
+// bounded pool of workers and folds the results into acc. The handler's
+// scanner is only read by a single producer goroutine and reduce is only called
+// from the calling goroutine, so neither needs to be safe for concurrent use.
+//
+// The first error returned by a mapper, or by the scanner, cancels the context
+// passed to the other mappers and is returned once they've stopped. Mapper
+// InvalidDataErrors are given the line number and input path.
+func MapReduceLines[R, A any](ctx context.Context, h *AdventHandler, mapFn LineMapper[R], reduceFn func(A, R) A, acc A, opts ...ParallelOption) (A, error) {
+	cfg := parallelConfig{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var errOnce sync.Once
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	jobs := make(chan lineJob, cfg.workers)
+	results := make(chan lineResult[R], cfg.workers)
+
+	firstLine := h.LineNum() + 1
+
+	var producer sync.WaitGroup
+	producer.Add(1)
+	go func() {
+		defer producer.Done()
+		defer close(jobs)
+		for idx := 0; h.Scan(); idx++ {
+			select {
+			case jobs <- lineJob{idx: idx, line: h.Text()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := h.Scanner.Err(); err != nil {
+			fail(err)
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for range cfg.workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				value, err := mapFn(ctx, job.line)
+				var dataErr *InvalidDataError
+				if errors.As(err, &dataErr) {
+					err = h.DataErrorAt(err, job.line, firstLine+job.idx)
+				}
+				if err != nil {
+					fail(err)
+					continue
+				}
+				cfg.progress.Increment()
+				select {
+				case results <- lineResult[R]{idx: job.idx, value: value}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	pending := map[int]R{}
+	next := 0
+	for result := range results {
+		if ctx.Err() != nil {
+			continue
+		}
+		if !cfg.ordered {
+			acc = reduceFn(acc, result.value)
+			continue
+		}
+
+		pending[result.idx] = result.value
+		for value, exists := pending[next]; exists; value, exists = pending[next] {
+			delete(pending, next)
+			acc = reduceFn(acc, value)
+			next++
+		}
+	}
+	producer.Wait()
+
+	if firstErr != nil {
+		return acc, firstErr
+	}
+	return acc, ctx.Err()
+}
+
+// MapLines maps each of the handler's remaining input lines in parallel,
+// returning the results in input order
+func MapLines[R any](ctx context.Context, h *AdventHandler, mapFn LineMapper[R], opts ...ParallelOption) ([]R, error) {
+	opts = append(opts, WithOrdered())
+	return MapReduceLines(ctx, h, mapFn, func(values []R, value R) []R {
+		return append(values, value)
+	}, []R{}, opts...)
+}
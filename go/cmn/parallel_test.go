@@ -0,0 +1,123 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: parallel_test
+	Description: Tests for the parallel line processors
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// lineHandler creates a handler reading the numbers 1 through n, one per line
+func lineHandler(t *testing.T, n int) *AdventHandler {
+	t.Helper()
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = strconv.Itoa(i + 1)
+	}
+
+	handler, err := NewHandlerE(nil,
+		WithPuzzle(1, 1, false),
+		WithReader(strings.NewReader(strings.Join(lines, "\n"))),
+		WithOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(handler.Close)
+	return handler
+}
+
+func atoi(ctx context.Context, line string) (int, error) {
+	return strconv.Atoi(line)
+}
+
+func TestMapReduceLinesUnordered(t *testing.T) {
+	sum, err := MapReduceLines(context.Background(), lineHandler(t, 1000), atoi,
+		func(acc, value int) int { return acc + value }, 0, WithWorkers(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 500500 {
+		t.Errorf("expected sum 500500, got %d", sum)
+	}
+}
+
+func TestMapLinesOrdered(t *testing.T) {
+	values, err := MapLines(context.Background(), lineHandler(t, 500), atoi, WithWorkers(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 500 {
+		t.Fatalf("expected 500 values, got %d", len(values))
+	}
+	for i, value := range values {
+		if value != i+1 {
+			t.Fatalf("value %d out of order: %d", i, value)
+		}
+	}
+}
+
+func TestMapReduceLinesError(t *testing.T) {
+	errBad := errors.New("bad line")
+	var mapped atomic.Int64
+
+	_, err := MapReduceLines(context.Background(), lineHandler(t, 10000),
+		func(ctx context.Context, line string) (int, error) {
+			mapped.Add(1)
+			if line == "10" {
+				return 0, fmt.Errorf("line %s: %w", line, errBad)
+			}
+			return strconv.Atoi(line)
+		},
+		func(acc, value int) int { return acc + value }, 0, WithWorkers(4))
+	if !errors.Is(err, errBad) {
+		t.Fatalf("expected the mapper's error, got %v", err)
+	}
+	if mapped.Load() == 10000 {
+		t.Error("expected the error to stop the remaining lines from being mapped")
+	}
+}
+
+func TestMapReduceLinesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := MapLines(ctx, lineHandler(t, 100), atoi)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: parse
+	Description: Helpers for parsing the puzzle data read by an AdventHandler
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FieldTag is the struct tag naming the field index a value is parsed from,
+// e.g. `field:"1"`, or `field:"2:"` for a slice of every field from the third on
+const FieldTag = "field"
+
+// FieldSeparators are the characters that split a line into fields for
+// ParseFields, in addition to whitespace
+const FieldSeparators = ",|:;"
+
+var intRe = regexp.MustCompile(`-?\d+`)
+
+// ExtractInts returns the signed integers found in arbitrary text, a '-' is
+// only treated as a sign if it isn't preceded by a digit
+func ExtractInts(text string) ([]int, error) {
+	nums := []int{}
+	for _, loc := range intRe.FindAllStringIndex(text, -1) {
+		start := loc[0]
+		if text[start] == '-' && start > 0 && text[start-1] >= '0' && text[start-1] <= '9' {
+			start++
+		}
+		num, err := strconv.Atoi(text[start:loc[1]])
+		if err != nil {
+			return nil, FieldDataError(text, start, text[start:loc[1]], "an integer that fits in an int")
+		}
+		nums = append(nums, num)
+	}
+	return nums, nil
+}
+
+// Lines returns the remaining lines of input
+func (h *AdventHandler) Lines() ([]string, error) {
+	lines := []string{}
+	for h.Scan() {
+		lines = append(lines, h.Text())
+	}
+	return lines, h.Scanner.Err()
+}
+
+// Blocks returns the remaining lines of input grouped by the blank lines
+// between them, leading, trailing and repeated blank lines are ignored
+func (h *AdventHandler) Blocks() ([][]string, error) {
+	blocks := [][]string{}
+	block := []string{}
+	for h.Scan() {
+		line := h.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = []string{}
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks, h.Scanner.Err()
+}
+
+// Sections returns the remaining lines of input as exactly n blocks, such as
+// the rules and updates of a puzzle
+func (h *AdventHandler) Sections(n int) ([][]string, error) {
+	blocks, err := h.Blocks()
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) != n {
+		return nil, h.DataErrorAt(&InvalidDataError{
+			Expected: fmt.Sprintf("%d sections separated by blank lines", n),
+			Found:    fmt.Sprint(len(blocks)),
+		}, "", h.LineNum())
+	}
+	return blocks, nil
+}
+
+// IntsPerLine extracts the signed integers from each remaining line of input
+func (h *AdventHandler) IntsPerLine() ([][]int, error) {
+	rows := [][]int{}
+	for h.Scan() {
+		nums, err := ExtractInts(h.Text())
+		if err != nil {
+			return nil, h.DataError(err)
+		}
+		rows = append(rows, nums)
+	}
+	return rows, h.Scanner.Err()
+}
+
+// RuneGrid returns the remaining lines of input as rows of runes, every row
+// must be the same length
+func (h *AdventHandler) RuneGrid() ([][]rune, error) {
+	return parseGrid(h, func(line string) []rune { return []rune(line) })
+}
+
+// ByteGrid returns the remaining lines of input as rows of bytes, every row
+// must be the same length
+func (h *AdventHandler) ByteGrid() ([][]byte, error) {
+	return parseGrid(h, func(line string) []byte { return []byte(line) })
+}
+
+func parseGrid[T any](h *AdventHandler, split func(string) []T) ([][]T, error) {
+	rows := [][]T{}
+	for h.Scan() {
+		row := split(h.Text())
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, h.DataError(&InvalidDataError{
+				Col:      min(len(row), len(rows[0])) + 1,
+				Expected: fmt.Sprintf("%d cells", len(rows[0])),
+				Found:    fmt.Sprint(len(row)),
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows, h.Scanner.Err()
+}
+
+// ParseLines parses each remaining line of input into a struct using
+// ParseFields
+func ParseLines[T any](h *AdventHandler) ([]T, error) {
+	values := []T{}
+	for h.Scan() {
+		var value T
+		if err := ParseFields(h.Text(), &value); err != nil {
+			return nil, h.DataError(err)
+		}
+		values = append(values, value)
+	}
+	return values, h.Scanner.Err()
+}
+
+// ParseFields splits the line on whitespace and FieldSeparators and stores the
+// fields in the tagged fields of the struct dst points to. Strings, bools,
+// integers, floats and slices of them are supported.
+//
+//	type Rule struct {
+//		Before int `field:"0"`
+//		After  int `field:"1"`
+//	}
+func ParseFields(line string, dst any) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ParseFields expects a pointer to a struct, got %T", dst)
+	}
+
+	fields := splitFields(line)
+
+	target := ptr.Elem()
+	for i := range target.NumField() {
+		structField := target.Type().Field(i)
+		tag, ok := structField.Tag.Lookup(FieldTag)
+		if !ok {
+			continue
+		}
+
+		idxStr, rest := strings.CutSuffix(tag, ":")
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil || idx < 0 {
+			return fmt.Errorf("invalid %s tag %q on %s", FieldTag, tag, structField.Name)
+		}
+		name := strings.ToLower(structField.Name)
+		value := target.Field(i)
+		if rest {
+			// A slice of the remaining fields may be empty
+			idx = min(idx, len(fields))
+			if value.Kind() != reflect.Slice {
+				return fmt.Errorf("%s must be a slice for tag %q", structField.Name, tag)
+			}
+			values := reflect.MakeSlice(value.Type(), len(fields)-idx, len(fields)-idx)
+			for j, field := range fields[idx:] {
+				if expected := setField(values.Index(j), field.text); expected != "" {
+					return FieldDataError(line, field.offset, field.text, expected+" for "+name)
+				}
+			}
+			value.Set(values)
+			continue
+		}
+
+		if idx >= len(fields) {
+			return &InvalidDataError{
+				Line:     line,
+				Col:      len(line) + 1,
+				Expected: fmt.Sprintf("at least %d fields for %s", idx+1, name),
+				Found:    fmt.Sprint(len(fields)),
+			}
+		}
+		if expected := setField(value, fields[idx].text); expected != "" {
+			return FieldDataError(line, fields[idx].offset, fields[idx].text, expected+" for "+name)
+		}
+	}
+	return nil
+}
+
+// field is a field of a line and its byte offset
+type field struct {
+	text   string
+	offset int
+}
+
+// splitFields splits the line on whitespace and FieldSeparators
+func splitFields(line string) []field {
+	fields := []field{}
+	start := -1
+	for i, r := range line {
+		isSep := r == ' ' || r == '\t' || strings.ContainsRune(FieldSeparators, r)
+		switch {
+		case isSep && start >= 0:
+			fields = append(fields, field{line[start:i], start})
+			start = -1
+		case !isSep && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, field{line[start:], start})
+	}
+	return fields
+}
+
+// setField parses the text into the value based on its kind, returning a
+// description of the expected text if it can't be parsed
+func setField(value reflect.Value, text string) (expected string) {
+	var err error
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(text); err == nil {
+			value.SetBool(b)
+		}
+		expected = "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(text, 10, value.Type().Bits()); err == nil {
+			value.SetInt(n)
+		}
+		expected = "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(text, 10, value.Type().Bits()); err == nil {
+			value.SetUint(n)
+		}
+		expected = "an unsigned integer"
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(text, value.Type().Bits()); err == nil {
+			value.SetFloat(f)
+		}
+		expected = "a number"
+	default:
+		return "a supported field type instead of " + value.Type().String()
+	}
+
+	if err != nil {
+		return expected
+	}
+	return ""
+}
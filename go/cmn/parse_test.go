@@ -0,0 +1,156 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: parse_test
+	Description: Tests for the input parsing helpers
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func dataHandler(t *testing.T, data string) *AdventHandler {
+	t.Helper()
+	handler, err := NewHandlerE(nil,
+		WithPuzzle(1, 1, false),
+		WithReader(strings.NewReader(data)),
+		WithOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(handler.Close)
+	return handler
+}
+
+func TestExtractInts(t *testing.T) {
+	nums, err := ExtractInts("p=0,-4 v=3,-3 range 10-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, -4, 3, -3, 10, 20}; !slices.Equal(nums, want) {
+		t.Errorf("expected %v, got %v", want, nums)
+	}
+}
+
+func TestBlocksAndSections(t *testing.T) {
+	data := "\n47|53\n97|13\n\n\n75,47,61\n\n"
+	blocks, err := dataHandler(t, data).Blocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 2 || len(blocks[0]) != 2 || blocks[1][0] != "75,47,61" {
+		t.Errorf("unexpected blocks: %q", blocks)
+	}
+
+	var dataErr *InvalidDataError
+	if _, err := dataHandler(t, data).Sections(3); !errors.As(err, &dataErr) {
+		t.Errorf("expected an InvalidDataError for the wrong section count, got %v", err)
+	}
+}
+
+func TestIntsPerLineError(t *testing.T) {
+	_, err := dataHandler(t, "1 2\n3 99999999999999999999\n").IntsPerLine()
+	var dataErr *InvalidDataError
+	if !errors.As(err, &dataErr) || dataErr.LineNum != 2 {
+		t.Fatalf("expected an InvalidDataError on line 2, got %v", err)
+	}
+}
+
+func TestByteGridRagged(t *testing.T) {
+	_, err := dataHandler(t, "abc\ndef\ngh\n").ByteGrid()
+	var dataErr *InvalidDataError
+	if !errors.As(err, &dataErr) || dataErr.LineNum != 3 || dataErr.Line != "gh" {
+		t.Fatalf("expected an InvalidDataError on line 3, got %v", err)
+	}
+}
+
+type testRecord struct {
+	Name   string  `field:"0"`
+	Count  int     `field:"1"`
+	Ratio  float64 `field:"2"`
+	Values []int   `field:"3:"`
+	Note   string
+}
+
+func TestParseLines(t *testing.T) {
+	records, err := ParseLines[testRecord](dataHandler(t, "a: 1 0.5 | 7,8,9\nb 2 1.5 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Name != "a" || records[0].Ratio != 0.5 || !slices.Equal(records[0].Values, []int{7, 8, 9}) {
+		t.Errorf("unexpected records: %+v", records)
+	}
+
+	_, err = ParseLines[testRecord](dataHandler(t, "a 1 0.5\nb x 1.5\n"))
+	var dataErr *InvalidDataError
+	if !errors.As(err, &dataErr) || dataErr.LineNum != 2 {
+		t.Fatalf("expected an InvalidDataError on line 2, got %v", err)
+	}
+}
+
+func TestFieldErrorDiagnostic(t *testing.T) {
+	handler := dataHandler(t, "1|2\n10|abc\n")
+	handler.InputPath = "rules.txt"
+
+	_, err := ParseLines[struct {
+		Before int `field:"0"`
+		After  int `field:"1"`
+	}](handler)
+
+	var dataErr *InvalidDataError
+	if !errors.As(err, &dataErr) {
+		t.Fatalf("expected an InvalidDataError, got %v", err)
+	}
+	if dataErr.LineNum != 2 || dataErr.Col != 4 || dataErr.EndCol != 7 || dataErr.Found != `"abc"` {
+		t.Errorf("unexpected error fields: %+v", dataErr)
+	}
+
+	want := "rules.txt:2:4: invalid data: expected an integer for after, found \"abc\"\n" +
+		" 2 | 10|abc\n" +
+		"   |    ^^^\n"
+	if err.Error() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, err)
+	}
+}
+
+func TestDataErrorWithoutColumn(t *testing.T) {
+	err := &InvalidDataError{Line: "abc", Err: errors.New("bad line")}
+	if want := "input: invalid data: bad line\n  | abc\n"; err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+
+	err = &InvalidDataError{LineNum: 3, Expected: "2 sections", Found: "1"}
+	if want := "input:3: invalid data: expected 2 sections, found 1\n"; err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
@@ -31,23 +31,176 @@ CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 package cmn
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
-// Profile logs the time it took to execute a task with the given name and start time
-func Profile(start time.Time, name string) {
-	elapsed := time.Since(start)
-	elapsedSeconds := elapsed.Seconds()
-	fmt.Printf("Process [%s] took %.5f seconds\n", name, elapsedSeconds)
+// ProfileOut is the writer that profiling results are logged to
+var ProfileOut io.Writer = os.Stdout
+
+// ProfileRecording determines if finished spans are kept for the profile
+// summary, disable it when running a task many times, e.g. in benchmarks
+var ProfileRecording = true
+
+// ProfileMemory determines if spans measure allocations and GCs. Reading the
+// memory stats stops the world, so it's only enabled when the measurements are
+// output, e.g. in the profile summary or the run records
+var ProfileMemory = false
+
+// SpanStats holds the measurements of a profiled task. Allocations and GC
+// counts are process wide, so they include any concurrently running tasks
+type SpanStats struct {
+	Name       string        `json:"name"`        // Name is the task name, nested spans are joined with /
+	Elapsed    time.Duration `json:"elapsed_ns"`  // Elapsed is the wall clock time of the task
+	Allocs     uint64        `json:"allocs"`      // Allocs is the number of heap objects allocated
+	AllocBytes uint64        `json:"alloc_bytes"` // AllocBytes is the number of heap bytes allocated
+	GCs        uint32        `json:"gcs"`         // GCs is the number of completed GC cycles
+
+	measuredMemory bool // measuredMemory is set if the allocations and GCs were measured
+}
+
+var (
+	spansMu sync.Mutex   // spansMu guards spans
+	spans   []*SpanStats // spans are all of the recorded spans, in the order they finished
+)
+
+// startSpan begins measuring a task, the returned function finishes the
+// measurement and records the span
+func startSpan(name string) func() *SpanStats {
+	measureMemory := ProfileMemory
+	var before runtime.MemStats
+	if measureMemory {
+		runtime.ReadMemStats(&before)
+	}
+	start := time.Now()
+
+	return func() *SpanStats {
+		span := &SpanStats{Name: name, Elapsed: time.Since(start), measuredMemory: measureMemory}
+		if measureMemory {
+			var after runtime.MemStats
+			runtime.ReadMemStats(&after)
+			span.Allocs = after.Mallocs - before.Mallocs
+			span.AllocBytes = after.TotalAlloc - before.TotalAlloc
+			span.GCs = after.NumGC - before.NumGC
+		}
+
+		if ProfileRecording {
+			spansMu.Lock()
+			spans = append(spans, span)
+			spansMu.Unlock()
+		}
+		return span
+	}
+}
+
+// Profile logs the measurements of a task, the allocations are only included if
+// they were measured
+func Profile(span *SpanStats) {
+	writeProfile(ProfileOut, span)
+}
+
+// writeProfile logs the measurements of a task to the writer
+func writeProfile(out io.Writer, span *SpanStats) {
+	if !span.measuredMemory {
+		fmt.Fprintf(out, "Process [%s] took %.5f seconds\n", span.Name, span.Elapsed.Seconds())
+		return
+	}
+	fmt.Fprintf(
+		out,
+		"Process [%s] took %.5f seconds, %d allocs (%d bytes), %d GCs\n",
+		span.Name,
+		span.Elapsed.Seconds(),
+		span.Allocs,
+		span.AllocBytes,
+		span.GCs,
+	)
 }
 
 // StartProfile returns a function that logs the time it took to execute a task with the given name
 // this can be used in combination defer to log the time it took to execute a task
-func StartProfile(taskName string) func() {
-	start := time.Now()
+func StartProfile(taskName string) func() time.Duration {
+	stop := startSpan(taskName)
+
+	return func() time.Duration {
+		span := stop()
+		Profile(span)
+		return span.Elapsed
+	}
+}
+
+// SpanTracker tracks nested spans, so that e.g. a "parse" span started inside
+// of a "day1/puzzle1" span is recorded as "day1/puzzle1/parse"
+type SpanTracker struct {
+	mu    sync.Mutex
+	stack []string
+}
+
+// Start begins a span nested inside of any spans that are still open, the
+// returned function ends it
+func (t *SpanTracker) Start(name string) func() *SpanStats {
+	t.mu.Lock()
+	t.stack = append(t.stack, name)
+	depth := len(t.stack)
+	stop := startSpan(strings.Join(t.stack, "/"))
+	t.mu.Unlock()
+
+	return func() *SpanStats {
+		span := stop()
+		t.mu.Lock()
+		t.stack = t.stack[:depth-1]
+		t.mu.Unlock()
+		return span
+	}
+}
+
+// ProfileSpans returns all of the spans recorded so far
+func ProfileSpans() []*SpanStats {
+	spansMu.Lock()
+	defer spansMu.Unlock()
+	return append([]*SpanStats{}, spans...)
+}
+
+// ValidateProfileFormat checks that the profile summary format is json or csv
+func ValidateProfileFormat(format string) error {
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("invalid profile format %q, expected json or csv", format)
+	}
+	return nil
+}
+
+// WriteProfileSummary writes the recorded spans to the writer in the given
+// format, either json or csv
+func WriteProfileSummary(w io.Writer, format string) error {
+	if err := ValidateProfileFormat(format); err != nil {
+		return err
+	}
+	recorded := ProfileSpans()
+
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(recorded)
+	}
 
-	return func() {
-		Profile(start, taskName)
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"name", "elapsed_ns", "allocs", "alloc_bytes", "gcs"})
+	for _, span := range recorded {
+		writer.Write([]string{
+			span.Name,
+			strconv.FormatInt(span.Elapsed.Nanoseconds(), 10),
+			strconv.FormatUint(span.Allocs, 10),
+			strconv.FormatUint(span.AllocBytes, 10),
+			strconv.FormatUint(uint64(span.GCs), 10),
+		})
 	}
+	writer.Flush()
+	return writer.Error()
 }
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: profile_test
+	Description: Tests for the profiling spans and their summary
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// useSpans clears the recorded spans for the test, restoring them afterwards
+func useSpans(t *testing.T) {
+	t.Helper()
+	spansMu.Lock()
+	recorded := spans
+	spans = nil
+	spansMu.Unlock()
+	t.Cleanup(func() {
+		spansMu.Lock()
+		spans = recorded
+		spansMu.Unlock()
+	})
+}
+
+func TestSpanTrackerNesting(t *testing.T) {
+	useSpans(t)
+	var tracker SpanTracker
+
+	endPuzzle := tracker.Start("day1/puzzle1")
+	tracker.Start("parse")()
+	endSolve := tracker.Start("solve")
+	tracker.Start("inner")()
+	endSolve()
+	endPuzzle()
+	tracker.Start("after")()
+
+	names := []string{}
+	for _, span := range ProfileSpans() {
+		names = append(names, span.Name)
+	}
+	expected := "day1/puzzle1/parse day1/puzzle1/solve/inner day1/puzzle1/solve day1/puzzle1 after"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("spans = %v, want %s", names, expected)
+	}
+}
+
+func TestSpanMemory(t *testing.T) {
+	useSpans(t)
+	defer func(enabled bool) { ProfileMemory = enabled }(ProfileMemory)
+
+	allocate := func() []byte { return bytes.Repeat([]byte("x"), 1<<16) }
+
+	ProfileMemory = false
+	stop := startSpan("without")
+	allocate()
+	if span := stop(); span.Allocs != 0 || span.AllocBytes != 0 {
+		t.Errorf("expected no allocations to be measured, got %+v", span)
+	}
+
+	ProfileMemory = true
+	stop = startSpan("with")
+	allocate()
+	if span := stop(); span.AllocBytes < 1<<16 {
+		t.Errorf("expected the allocations to be measured, got %+v", span)
+	}
+}
+
+func TestProfileOutput(t *testing.T) {
+	defer func(out io.Writer) { ProfileOut = out }(ProfileOut)
+	var out bytes.Buffer
+	ProfileOut = &out
+
+	Profile(&SpanStats{Name: "task", Elapsed: 1500000})
+	Profile(&SpanStats{Name: "task", Elapsed: 1500000, Allocs: 2, AllocBytes: 64, measuredMemory: true})
+	expected := "Process [task] took 0.00150 seconds\n" +
+		"Process [task] took 0.00150 seconds, 2 allocs (64 bytes), 0 GCs\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestWriteProfileSummary(t *testing.T) {
+	useSpans(t)
+	spans = []*SpanStats{
+		{Name: "day1/puzzle1", Elapsed: 2000, Allocs: 3, AllocBytes: 96, GCs: 1},
+		{Name: "day1/puzzle2", Elapsed: 1000},
+	}
+
+	var out bytes.Buffer
+	if err := WriteProfileSummary(&out, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "name,elapsed_ns,allocs,alloc_bytes,gcs\n" +
+		"day1/puzzle1,2000,3,96,1\n" +
+		"day1/puzzle2,1000,0,0,0\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	if err := WriteProfileSummary(&out, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[0]["name"] != "day1/puzzle1" || decoded[0]["elapsed_ns"] != 2000.0 {
+		t.Errorf("unexpected summary: %v", decoded)
+	}
+
+	if err := WriteProfileSummary(&out, "xml"); err == nil {
+		t.Error("expected an error for an invalid format")
+	}
+}
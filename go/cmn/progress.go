@@ -0,0 +1,235 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: progress
+	Description: Progress reporting for long running solvers
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ProgressOut is the writer progress is rendered to, setting it to io.Discard
+// also silences the progress log lines
+var ProgressOut io.Writer = os.Stderr
+
+// ProgressEnabled turns progress reporting on or off, it's off under tests and
+// when the output is meant for other programs, e.g. JSON logs
+var ProgressEnabled = !testing.Testing()
+
+const (
+	// progressBarWidth is the number of characters in a rendered progress bar
+	progressBarWidth = 30
+	// progressRenderInterval throttles redrawing the progress bar on a terminal
+	progressRenderInterval = 100 * time.Millisecond
+	// progressLogInterval throttles the progress log lines when not on a terminal
+	progressLogInterval = 2 * time.Second
+)
+
+var spinnerFrames = []rune(`|/-\`)
+
+// Progress tracks how far a solver has gotten through a task. On a terminal it's
+// rendered as a single updating line, otherwise it's logged periodically. All
+// methods are safe for concurrent use and a nil or disabled Progress still
+// counts but renders nothing.
+type Progress struct {
+	name    string
+	total   atomic.Int64
+	current atomic.Int64
+	start   time.Time
+	enabled bool
+	tty     bool
+	out     io.Writer
+	log     *slog.Logger
+
+	mu       sync.Mutex
+	last     time.Time
+	interval time.Duration
+	frame    int
+	done     bool
+}
+
+// NewProgress creates a progress tracker for a task with the given number of
+// steps, a total of 0 is indeterminate and rendered as a spinner
+func NewProgress(name string, total int, log *slog.Logger) *Progress {
+	return newProgress(name, total, log, ProgressOut)
+}
+
+// newProgress creates a progress tracker that renders to out
+func newProgress(name string, total int, log *slog.Logger, out io.Writer) *Progress {
+	p := &Progress{
+		name:     name,
+		start:    time.Now(),
+		enabled:  ProgressEnabled && out != io.Discard,
+		tty:      isTerminal(out),
+		out:      out,
+		log:      log,
+		interval: progressLogInterval,
+	}
+	if p.tty {
+		p.interval = progressRenderInterval
+	}
+	if p.log == nil {
+		p.log = slog.Default()
+	}
+	p.total.Store(int64(total))
+	return p
+}
+
+// Progress creates a progress tracker for one of the solver's tasks, rendered to
+// the handler's progress output and logged with the day's logger when not on a
+// terminal
+func (h *AdventHandler) Progress(name string, total int) *Progress {
+	out := h.progressOut
+	if out == nil {
+		out = ProgressOut
+	}
+	return newProgress(name, total, h.Log, out)
+}
+
+// isTerminal checks if the writer is a character device such as a terminal
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Add advances the progress by n steps
+func (p *Progress) Add(n int) {
+	if p == nil {
+		return
+	}
+	p.current.Add(int64(n))
+	p.render(false)
+}
+
+// Increment advances the progress by a single step
+func (p *Progress) Increment() {
+	p.Add(1)
+}
+
+// SetTotal changes the number of steps, 0 makes the progress indeterminate
+func (p *Progress) SetTotal(total int) {
+	if p == nil {
+		return
+	}
+	p.total.Store(int64(total))
+}
+
+// Current returns the number of steps completed so far
+func (p *Progress) Current() int {
+	if p == nil {
+		return 0
+	}
+	return int(p.current.Load())
+}
+
+// Done stops the progress, rendering the final state. Calling it more than once
+// has no effect.
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	p.render(true)
+}
+
+// render draws or logs the progress if enough time has passed since the last
+// update, or unconditionally when finishing
+func (p *Progress) render(final bool) {
+	if !p.enabled {
+		return
+	}
+
+	if final {
+		p.mu.Lock()
+	} else if !p.mu.TryLock() {
+		// Another goroutine is already rendering
+		return
+	}
+	defer p.mu.Unlock()
+
+	now := time.Now()
+
+	if p.done || (!final && now.Sub(p.last) < p.interval) {
+		return
+	}
+	p.last = now
+	p.done = final
+
+	current, total := p.current.Load(), p.total.Load()
+	elapsed := now.Sub(p.start).Round(time.Millisecond)
+
+	if !p.tty {
+		args := []any{"task", p.name, "current", current, "elapsed", elapsed}
+		if total > 0 {
+			args = append(args, "total", total, "percent", fmt.Sprintf("%.1f", percent(current, total)))
+		}
+		if final {
+			p.log.Info("progress done", args...)
+		} else {
+			p.log.Info("progress", args...)
+		}
+		return
+	}
+
+	line := p.name + " "
+	if total > 0 {
+		filled := int(percent(current, total) / 100 * progressBarWidth)
+		line += fmt.Sprintf("[%s%s] %3.0f%% %d/%d", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), percent(current, total), current, total)
+	} else {
+		frame := spinnerFrames[p.frame%len(spinnerFrames)]
+		if final {
+			frame = ' '
+		}
+		p.frame++
+		line += fmt.Sprintf("%c %d", frame, current)
+	}
+	line += " " + elapsed.String()
+
+	// Redraw the line in place, clearing any leftover characters
+	fmt.Fprintf(p.out, "\r%s\x1b[K", line)
+	if final {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// percent returns current as a percentage of total, capped at 100
+func percent(current, total int64) float64 {
+	return min(float64(current)/float64(total)*100, 100)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: progress_test
+	Description: Tests for progress rendering
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testProgress creates an enabled progress that renders to a buffer
+func testProgress(t *testing.T, total int, tty bool) (*Progress, *bytes.Buffer) {
+	t.Helper()
+	var out bytes.Buffer
+	p := NewProgress("task", total, slog.New(slog.NewTextHandler(&out, nil)))
+	p.enabled, p.tty, p.out = true, tty, &out
+	return p, &out
+}
+
+func TestProgressDisabledUnderTests(t *testing.T) {
+	if ProgressEnabled {
+		t.Fatal("expected progress to be disabled under tests")
+	}
+
+	var out bytes.Buffer
+	p := NewProgress("task", 10, slog.New(slog.NewTextHandler(&out, nil)))
+	p.Add(5)
+	p.Done()
+	if out.Len() != 0 || p.Current() != 5 {
+		t.Errorf("expected a silent progress that still counts, got %q and %d", out.String(), p.Current())
+	}
+
+	var nilProgress *Progress
+	nilProgress.Increment()
+	nilProgress.Done()
+}
+
+func TestProgressBar(t *testing.T) {
+	p, out := testProgress(t, 4, true)
+	p.Increment() // first update renders immediately
+	p.Increment() // throttled
+	p.Add(2)      // throttled
+	p.Done()
+	p.Done() // no effect
+
+	lines := strings.Split(out.String(), "\r")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 renders, got %q", out.String())
+	}
+	if !strings.Contains(lines[1], " 25% 1/4") || !strings.Contains(lines[2], "[==============================] 100% 4/4") {
+		t.Errorf("unexpected renders: %q", lines[1:])
+	}
+	if !strings.HasSuffix(out.String(), "\n") {
+		t.Error("expected the final render to end the line")
+	}
+}
+
+func TestProgressSpinnerConcurrent(t *testing.T) {
+	p, out := testProgress(t, 0, true)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				p.Increment()
+			}
+		}()
+	}
+	wg.Wait()
+	p.Done()
+
+	if p.Current() != 8000 || !strings.Contains(out.String(), "task   8000") {
+		t.Errorf("unexpected final spinner render: %q", out.String())
+	}
+}
+
+func TestProgressLogLines(t *testing.T) {
+	p, out := testProgress(t, 10, false)
+	p.Add(3)
+	p.Done()
+
+	logs := out.String()
+	if !strings.Contains(logs, "msg=progress task=task current=3") || !strings.Contains(logs, `msg="progress done" task=task current=3`) {
+		t.Errorf("unexpected progress logs: %q", logs)
+	}
+	if strings.Contains(logs, "\r") {
+		t.Error("expected no terminal control characters in the logs")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: record
+	Description: Machine-readable records of puzzle runs
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Output formats for the results of a run
+const (
+	FormatText = "text" // FormatText is the free-form output printed by the solvers
+	FormatJSON = "json" // FormatJSON is one JSON record per line
+	FormatCSV  = "csv"  // FormatCSV is a header followed by one row per record
+)
+
+// ValidateFormat checks that the output format is one of the supported formats
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON, FormatCSV:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q, expected text, json or csv", format)
+}
+
+// RunRecord is the machine-readable outcome of solving a single puzzle
+type RunRecord struct {
+	Day        int           `json:"day"`         // Day is the day number
+	Part       int           `json:"part"`        // Part is the puzzle number
+	Sample     bool          `json:"sample"`      // Sample is set if the sample data was used
+	Answer     string        `json:"answer"`      // Answer is the reported answer, empty if none was reported
+	Elapsed    time.Duration `json:"elapsed_ns"`  // Elapsed is the wall clock time of the solver
+	Allocs     uint64        `json:"allocs"`      // Allocs is the number of heap objects allocated by the process during the run
+	AllocBytes uint64        `json:"alloc_bytes"` // AllocBytes is the number of heap bytes allocated by the process during the run
+	Concurrent bool          `json:"concurrent"`  // Concurrent is set if other runs were solving at the same time, their allocations are then included
+	InputHash  string        `json:"input_hash"`  // InputHash is the hex SHA-256 of the whole (decompressed) input
+	Error      string        `json:"error"`       // Error is the error returned by the run, empty on success
+}
+
+// recordHeader is the CSV header, in the order of the RunRecord fields
+var recordHeader = []string{"day", "part", "sample", "answer", "elapsed_ns", "allocs", "alloc_bytes", "concurrent", "input_hash", "error"}
+
+// RecordWriter writes run records in the json or csv format
+type RecordWriter struct {
+	format  string
+	encoder *json.Encoder
+	csv     *csv.Writer
+	header  bool
+}
+
+// NewRecordWriter returns a writer for the json or csv format. The text format
+// has no records, the solvers' own output is used instead
+func NewRecordWriter(w io.Writer, format string) (*RecordWriter, error) {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &RecordWriter{format: format, encoder: encoder}, nil
+	case FormatCSV:
+		return &RecordWriter{format: format, csv: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("invalid record format %q, expected json or csv", format)
+}
+
+// Write writes a single record, the CSV header is written before the first one
+func (w *RecordWriter) Write(record *RunRecord) error {
+	if w.format == FormatJSON {
+		return w.encoder.Encode(record)
+	}
+
+	if !w.header {
+		w.csv.Write(recordHeader)
+		w.header = true
+	}
+	w.csv.Write([]string{
+		strconv.Itoa(record.Day),
+		strconv.Itoa(record.Part),
+		strconv.FormatBool(record.Sample),
+		record.Answer,
+		strconv.FormatInt(record.Elapsed.Nanoseconds(), 10),
+		strconv.FormatUint(record.Allocs, 10),
+		strconv.FormatUint(record.AllocBytes, 10),
+		strconv.FormatBool(record.Concurrent),
+		record.InputHash,
+		record.Error,
+	})
+	// Flush each row so that records of long runs show up as they finish
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: record_test
+	Description: Tests for the machine-readable run records
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSolveWritesRecord(t *testing.T) {
+	var out bytes.Buffer
+	records, err := NewRecordWriter(&out, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := solveHandler(t, context.Background(), func(h *AdventHandler) error {
+		sum := 0
+		for h.Scan() {
+			sum += len(h.Text())
+		}
+		h.Report("Sum:", sum)
+		return nil
+	})
+	WithRecords(records)(handler)
+	if err := handler.Solve(); err != nil {
+		t.Fatal(err)
+	}
+
+	var record RunRecord
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("1\n2\n3\n"))
+	if record.Day != 1 || record.Part != 1 || record.Sample || record.Answer != "3" || record.Error != "" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if record.InputHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected the hash of the input, got %s", record.InputHash)
+	}
+	if record.Elapsed <= 0 {
+		t.Errorf("expected the elapsed time to be measured, got %v", record.Elapsed)
+	}
+}
+
+func TestWriteRecordMissingInput(t *testing.T) {
+	var out bytes.Buffer
+	records, err := NewRecordWriter(&out, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, err := NewHandlerE(nil, WithPuzzle(1, 2, true), WithInput("does/not/exist.txt"), WithRecords(records))
+	defer handler.Close()
+	if err = handler.WriteRecord(err); ExitCode(err) != ExitMissingInput {
+		t.Fatalf("expected the missing input error to be returned, got %v", err)
+	}
+
+	var record RunRecord
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Day != 1 || record.Part != 2 || !record.Sample || record.InputHash != "" ||
+		!strings.Contains(record.Error, "does/not/exist.txt") {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
+
+func TestRecordHashesWholeInput(t *testing.T) {
+	// More data than the scanner buffers, so the solver leaves some unread
+	data := strings.Repeat("12345\n", 2000)
+	handler, err := NewHandlerE(nil,
+		WithPuzzle(1, 1, false),
+		WithReader(strings.NewReader(data)),
+		WithOutput(io.Discard),
+		WithSolvers(func(h *AdventHandler) error {
+			h.Scan()
+			h.Report("First:", h.Text())
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer handler.Close()
+	record := handler.Record(handler.Solve())
+
+	sum := sha256.Sum256([]byte(data))
+	if record.Answer != "12345" || record.InputHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected the hash of the whole input, got %+v", record)
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	handler := solveHandler(t, context.Background(), func(h *AdventHandler) error {
+		return errors.New("bad input\n")
+	})
+	record := handler.Record(handler.Solve())
+	if record.Error != "bad input" || record.Answer != "" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
+
+func TestRecordWriterCSV(t *testing.T) {
+	var out bytes.Buffer
+	records, err := NewRecordWriter(&out, FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records.Write(&RunRecord{Day: 2, Part: 1, Sample: true, Answer: "2", Elapsed: 1500})
+	records.Write(&RunRecord{Day: 2, Part: 2, Error: "failed, badly"})
+
+	expected := "day,part,sample,answer,elapsed_ns,allocs,alloc_bytes,concurrent,input_hash,error\n" +
+		"2,1,true,2,1500,0,0,false,,\n" +
+		"2,2,false,,0,0,0,false,,\"failed, badly\"\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	if _, err := NewRecordWriter(&out, FormatText); err == nil {
+		t.Error("expected an error for the text format")
+	}
+	if err := ValidateFormat("xml"); err == nil || !strings.Contains(err.Error(), "xml") {
+		t.Errorf("expected an invalid format error, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: registry
+	Description: Registry of the daily commands and their solver functions
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// DailyPuzzle holds the command and solver functions registered for a day
+type DailyPuzzle struct {
+	DayNum  int            // DayNum is the day of the Advent of Code challenge
+	Cmd     *cobra.Command // Cmd is the cobra command for the day
+	Solvers []HandlerFunc  // Solvers are the solver functions, one per puzzle
+}
+
+// registry maps the day numbers to their registered puzzles
+var registry = map[int]*DailyPuzzle{}
+
+// RegisterDay adds the command and solvers for a day to the registry
+func RegisterDay(day int, cmd *cobra.Command, solvers ...HandlerFunc) {
+	registry[day] = &DailyPuzzle{DayNum: day, Cmd: cmd, Solvers: solvers}
+}
+
+// GetDay retrieves the registered puzzle for the given day, if any
+func GetDay(day int) (puzzle *DailyPuzzle, ok bool) {
+	puzzle, ok = registry[day]
+	return puzzle, ok
+}
+
+// GetDayOfCmd retrieves the registered puzzle the command belongs to, if any
+func GetDayOfCmd(cmd *cobra.Command) (puzzle *DailyPuzzle, ok bool) {
+	for _, puzzle := range registry {
+		if puzzle.Cmd == cmd {
+			return puzzle, true
+		}
+	}
+	return nil, false
+}
+
+// CmdDay returns the day the command runs. A daily command is bound to the day
+// it was registered for, so a day-num flag naming another day is rejected
+func CmdDay(cmd *cobra.Command) (int, error) {
+	day := GetFlagIntD(cmd, "day-num", 1)
+	puzzle, ok := GetDayOfCmd(cmd)
+	if !ok {
+		return day, nil
+	}
+	if cmd.Flags().Changed("day-num") && day != puzzle.DayNum {
+		return 0, fmt.Errorf("%s solves day %d, not day %d", cmd.Name(), puzzle.DayNum, day)
+	}
+	return puzzle.DayNum, nil
+}
+
+// HasSolver checks if a solver function has been registered for the puzzle
+func (p *DailyPuzzle) HasSolver(puzzleNum int) bool {
+	return puzzleNum >= 1 && puzzleNum <= len(p.Solvers) && p.Solvers[puzzleNum-1] != nil
+}
+
+// PuzzleNums returns the numbers of the puzzles that have a registered solver
+func (p *DailyPuzzle) PuzzleNums() []int {
+	puzzleNums := []int{}
+	for puzzleNum := 1; puzzleNum <= len(p.Solvers); puzzleNum++ {
+		if p.HasSolver(puzzleNum) {
+			puzzleNums = append(puzzleNums, puzzleNum)
+		}
+	}
+	return puzzleNums
+}
+
+// HasSolver checks if a solver function has been registered for the day and
+// puzzle number
+func HasSolver(day, puzzleNum int) bool {
+	puzzle, ok := GetDay(day)
+	return ok && puzzle.HasSolver(puzzleNum)
+}
+
+// RegisteredDays returns all of the registered puzzles that have at least one
+// solver, sorted by day number
+func RegisteredDays() []*DailyPuzzle {
+	days := []*DailyPuzzle{}
+	for _, puzzle := range registry {
+		if len(puzzle.Solvers) > 0 {
+			days = append(days, puzzle)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].DayNum < days[j].DayNum
+	})
+	return days
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: samples
+	Description: Code for extracting the sample data from saved puzzle description pages
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PuzzleHTMLFile is the default name of the saved puzzle description page in
+// each day's data directory
+const PuzzleHTMLFile = "puzzle.html"
+
+var (
+	preCodeRe  = regexp.MustCompile(`(?s)<pre><code>(.*?)</code></pre>`)
+	emAnswerRe = regexp.MustCompile(`(?s)<code><em>(.*?)</em></code>|<em><code>(.*?)</code></em>`)
+)
+
+// PuzzleSample is the example input and expected answer of a puzzle part
+type PuzzleSample struct {
+	PuzzleNum int    // PuzzleNum is the part the sample belongs to
+	Input     string // Input is the example input, empty if the part has none
+	Answer    string // Answer is the emphasized expected answer, empty if none was found
+}
+
+// ExtractSamples parses the puzzle description page, returning a sample for
+// each part's article. The first <pre><code> block of an article is used as the
+// input, parts without one reuse the previous part's input. The last
+// emphasized code value of an article is used as the answer
+func ExtractSamples(page string) ([]*PuzzleSample, error) {
+	articles := articleRe.FindAllStringSubmatch(page, -1)
+	if len(articles) == 0 {
+		return nil, errors.New("no puzzle descriptions found in the page")
+	}
+
+	samples := []*PuzzleSample{}
+	for i, article := range articles {
+		sample := &PuzzleSample{PuzzleNum: i + 1}
+
+		if block := preCodeRe.FindStringSubmatch(article[1]); block != nil {
+			sample.Input = htmlText(block[1])
+		} else if i > 0 {
+			sample.Input = samples[i-1].Input
+		}
+
+		if answers := emAnswerRe.FindAllStringSubmatch(article[1], -1); len(answers) > 0 {
+			last := answers[len(answers)-1]
+			sample.Answer = strings.TrimSpace(htmlText(last[1] + last[2]))
+		}
+
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// WriteSamples writes the sample inputs to the day's data directory and adds
+// their answers to the answers file. A single sample.txt is written if every
+// part has the same input, otherwise one file per part (e.g. sample2.txt).
+// Existing sample files with content and existing answers are only replaced if
+// force is set
+func WriteSamples(day int, samples []*PuzzleSample, force bool) (paths []string, err error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples to write")
+	}
+
+	shared := true
+	for _, sample := range samples[1:] {
+		if sample.Input != samples[0].Input {
+			shared = false
+		}
+	}
+
+	writeSample := func(fileName, input string) error {
+		path := filepath.Join(DayDir(day), fileName)
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 && !force {
+			return fmt.Errorf("%s already has content, use --force to replace it", path)
+		}
+		if err := WriteFileAtomic(path, []byte(input)); err != nil {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	}
+
+	if shared {
+		if err = writeSample(SampleFileName, samples[0].Input); err != nil {
+			return paths, err
+		}
+	} else {
+		for _, sample := range samples {
+			fileName := fmt.Sprintf("%s%d.txt", sampleFilePrefix, sample.PuzzleNum)
+			if err = writeSample(fileName, sample.Input); err != nil {
+				return paths, err
+			}
+		}
+	}
+
+	for _, sample := range samples {
+		if sample.Answer == "" {
+			continue
+		}
+		save := SaveAnswer
+		if force {
+			save = ReplaceAnswer
+		}
+		if err = save(day, sample.PuzzleNum, true, sample.Answer); err != nil {
+			return paths, err
+		}
+	}
+	return paths, nil
+}
+
+// htmlText strips the tags from the HTML fragment and unescapes its entities
+func htmlText(fragment string) string {
+	return html.UnescapeString(tagRe.ReplaceAllString(fragment, ""))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: samples_test
+	Description: Tests for extracting the samples from a puzzle description page
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const samplesPage = `<html><body><main>
+<article class="day-desc"><h2>--- Day 1: Test ---</h2>
+<p>For example:</p>
+<pre><code>3   4
+4   3
+a &lt; b
+</code></pre>
+<p>Adding up the distances gives <code>1 + 2</code>, a total of <code><em>11</em></code>!</p>
+</article>
+<p>Your puzzle answer was <code>42</code>.</p>
+<article class="day-desc"><h2 id="part2">--- Part Two ---</h2>
+<p>Using the same example, the <em>first</em> score is <em><code>9</code></em> and the total is <em><code>31</code></em>.</p>
+</article>
+</main></body></html>`
+
+func TestExtractSamples(t *testing.T) {
+	samples, err := ExtractSamples(samplesPage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "3   4\n4   3\na < b\n"
+	expected := []*PuzzleSample{
+		{PuzzleNum: 1, Input: input, Answer: "11"},
+		{PuzzleNum: 2, Input: input, Answer: "31"},
+	}
+	if !reflect.DeepEqual(samples, expected) {
+		for _, sample := range samples {
+			t.Logf("%+v", *sample)
+		}
+		t.Fatal("unexpected samples")
+	}
+
+	if _, err = ExtractSamples("<html><p>no puzzle</p></html>"); err == nil {
+		t.Error("expected an error for a page without articles")
+	}
+}
+
+func TestExtractSamplesSeparateInputs(t *testing.T) {
+	page := `<article><pre><code>1</code></pre><code><em>1</em></code></article>
+<article><pre><code>2</code></pre><p>no answer here</p></article>`
+	samples, err := ExtractSamples(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Input != "1" || samples[1].Input != "2" || samples[1].Answer != "" {
+		t.Errorf("unexpected samples: %+v, %+v", *samples[0], *samples[1])
+	}
+}
+
+func TestWriteSamples(t *testing.T) {
+	useTempDataDir(t)
+
+	shared := []*PuzzleSample{{PuzzleNum: 1, Input: "a\n", Answer: "1"}, {PuzzleNum: 2, Input: "a\n", Answer: "2"}}
+	paths, err := WriteSamples(1, shared, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(DayDir(1), SampleFileName)}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	answers, err := LoadAnswers(1)
+	if err != nil || answers["sample1"] != "1" || answers["sample2"] != "2" {
+		t.Errorf("answers = %v, err = %v", answers, err)
+	}
+
+	// Existing content is kept unless forced
+	if _, err = WriteSamples(1, shared, false); err == nil {
+		t.Error("expected an error replacing the existing sample")
+	}
+
+	// Forcing replaces the answers in place too, keeping the other entries
+	if err = SaveAnswer(1, 1, false, "10"); err != nil {
+		t.Fatal(err)
+	}
+	shared[1].Answer = "3"
+	if _, err = WriteSamples(1, shared, true); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(DayDir(1), AnswersFile))
+	if string(data) != "sample1: 1\nsample2: 3\npuzzle1: 10\n" {
+		t.Errorf("answers file = %q", data)
+	}
+
+	separate := []*PuzzleSample{{PuzzleNum: 1, Input: "a\n"}, {PuzzleNum: 2, Input: "b\n"}}
+	if paths, err = WriteSamples(2, separate, false); err != nil || len(paths) != 2 {
+		t.Fatalf("paths = %v, err = %v", paths, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(DayDir(2), "sample2.txt")); string(data) != "b\n" {
+		t.Errorf("sample2.txt = %q", data)
+	}
+}
+
+func TestWriteSamplesFailures(t *testing.T) {
+	useTempDataDir(t)
+
+	if _, err := WriteSamples(1, nil, false); err == nil {
+		t.Error("expected an error without samples")
+	}
+
+	// The day's data directory can't be created over a file
+	if err := os.WriteFile(DayDir(3), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := WriteSamples(3, []*PuzzleSample{{PuzzleNum: 1, Input: "a\n"}}, false)
+	if err == nil || len(paths) != 0 {
+		t.Errorf("expected no paths for the failed write, got %v, err = %v", paths, err)
+	}
+}
@@ -31,90 +31,73 @@ CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 package cmn
 
 import (
-	"sort"
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
 	"sync"
 )
 
-// Comparable interface defines types that support comparison operations.
-type Comparable interface {
-	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64 | ~string
-}
-
-// Set is a custom data structure to represent a mathematical set
-type Set[T Comparable] struct {
-	elements map[T]struct{}
-	mu       sync.RWMutex
-}
+// PlainSet is a mathematical set without any locking, for use from a single
+// goroutine. The zero value is not usable, create one with NewPlainSet.
+type PlainSet[T comparable] map[T]struct{}
 
-// NewSet creates and returns a new TypedSet
-func NewSet[T Comparable](items ...T) *Set[T] {
-	set := &Set[T]{elements: make(map[T]struct{})}
-	for _, item := range items {
-		set.Add(item)
-	}
+// NewPlainSet creates and returns a new PlainSet
+func NewPlainSet[T comparable](items ...T) PlainSet[T] {
+	set := make(PlainSet[T], len(items))
+	set.Add(items...)
 	return set
 }
 
-// Add inserts an element into the set
-func (s *Set[T]) Add(element T) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.elements[element] = struct{}{}
+// Add inserts elements into the set
+func (s PlainSet[T]) Add(elements ...T) {
+	for _, element := range elements {
+		s[element] = struct{}{}
+	}
 }
 
 // Remove deletes an element from the set
-func (s *Set[T]) Remove(element T) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	delete(s.elements, element)
+func (s PlainSet[T]) Remove(element T) {
+	delete(s, element)
 }
 
 // Contains checks if an element is in the set
-func (s *Set[T]) Contains(element T) bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	_, exists := s.elements[element]
+func (s PlainSet[T]) Contains(element T) bool {
+	_, exists := s[element]
 	return exists
 }
 
 // Len returns the number of elements in the set
-func (s *Set[T]) Len() int {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	return len(s.elements)
+func (s PlainSet[T]) Len() int {
+	return len(s)
+}
+
+// All iterates over the elements in no particular order
+func (s PlainSet[T]) All() iter.Seq[T] {
+	return maps.Keys(s)
 }
 
 // Values returns all elements in the set as a slice
-func (s *Set[T]) Values() []T {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	keys := make([]T, 0, len(s.elements))
-	for key := range s.elements {
-		keys = append(keys, key)
-	}
-	return keys
+func (s PlainSet[T]) Values() []T {
+	return slices.Collect(s.All())
 }
 
-func (s *Set[T]) SortedValues() []T {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	keys := make([]T, 0, len(s.elements))
-	for key := range s.elements {
-		keys = append(keys, key)
-	}
-	sort.Slice(keys, func(i, j int) bool {
-		return keys[i] < keys[j]
-	})
-	return keys
+// Clone returns a copy of the set
+func (s PlainSet[T]) Clone() PlainSet[T] {
+	return maps.Clone(s)
 }
 
-func (s *Set[T]) EquivalentTo(other *Set[T]) bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if s.Len() != other.Len() {
+// EquivalentTo checks if both sets have the same elements
+func (s PlainSet[T]) EquivalentTo(other PlainSet[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// IsSubset checks if every element of the set is in the other set
+func (s PlainSet[T]) IsSubset(other PlainSet[T]) bool {
+	if len(s) > len(other) {
 		return false
 	}
-	for _, element := range s.Values() {
+	for element := range s {
 		if !other.Contains(element) {
 			return false
 		}
@@ -122,64 +105,199 @@ func (s *Set[T]) EquivalentTo(other *Set[T]) bool {
 	return true
 }
 
-func (s *Set[T]) Union(other *Set[T]) *Set[T] {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	result := NewSet[T]()
+// IsSuperset checks if every element of the other set is in the set
+func (s PlainSet[T]) IsSuperset(other PlainSet[T]) bool {
+	return other.IsSubset(s)
+}
 
-	// Add all elements from the first set
-	for elem := range s.elements {
-		result.Add(elem)
+// Union returns a set of the elements in either set
+func (s PlainSet[T]) Union(other PlainSet[T]) PlainSet[T] {
+	result := s.Clone()
+	for element := range other {
+		result.Add(element)
 	}
+	return result
+}
 
-	// Add all elements from the second set
-	for elem := range other.elements {
-		result.Add(elem)
+// Intersection returns a set of the elements in both sets
+func (s PlainSet[T]) Intersection(other PlainSet[T]) PlainSet[T] {
+	// Iterate over the smaller set for efficiency
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
 	}
 
+	result := NewPlainSet[T]()
+	for element := range small {
+		if large.Contains(element) {
+			result.Add(element)
+		}
+	}
 	return result
 }
 
-func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	result := NewSet[T]()
-
-	// Iterate over the smaller set for efficiency
-	if s.Len() < other.Len() {
-		for elem := range s.elements {
-			if other.Contains(elem) {
-				result.Add(elem)
-			}
+// Difference returns a set of the elements in the set but not the other set
+func (s PlainSet[T]) Difference(other PlainSet[T]) PlainSet[T] {
+	result := NewPlainSet[T]()
+	for element := range s {
+		if !other.Contains(element) {
+			result.Add(element)
 		}
-	} else {
-		for elem := range other.elements {
-			if s.Contains(elem) {
-				result.Add(elem)
-			}
+	}
+	return result
+}
+
+// SymmetricDifference returns a set of the elements in exactly one of the sets
+func (s PlainSet[T]) SymmetricDifference(other PlainSet[T]) PlainSet[T] {
+	result := s.Difference(other)
+	for element := range other {
+		if !s.Contains(element) {
+			result.Add(element)
 		}
 	}
+	return result
+}
+
+// Set is a mathematical set that's safe for concurrent use. Operations on two
+// sets copy the other set under its own lock first, so they never hold both
+// locks and can't deadlock, even when called on the same set.
+type Set[T comparable] struct {
+	elements PlainSet[T]
+	mu       sync.RWMutex
+}
 
+// NewSet creates and returns a new Set
+func NewSet[T comparable](items ...T) *Set[T] {
+	return &Set[T]{elements: NewPlainSet(items...)}
+}
+
+// read runs the function with the elements under the read lock
+func (s *Set[T]) read(fn func(PlainSet[T])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.elements)
+}
+
+// combine applies the operation to the set and a copy of the other set
+func (s *Set[T]) combine(other *Set[T], op func(s, other PlainSet[T]) PlainSet[T]) *Set[T] {
+	otherElements := other.Plain()
+	result := &Set[T]{}
+	s.read(func(elements PlainSet[T]) {
+		result.elements = op(elements, otherElements)
+	})
 	return result
 }
 
-func (s *Set[T]) Complement(other *Set[T]) *Set[T] {
+// compare applies the check to the set and a copy of the other set
+func (s *Set[T]) compare(other *Set[T], check func(s, other PlainSet[T]) bool) (ok bool) {
+	otherElements := other.Plain()
+	s.read(func(elements PlainSet[T]) {
+		ok = check(elements, otherElements)
+	})
+	return ok
+}
+
+// Add inserts elements into the set
+func (s *Set[T]) Add(elements ...T) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	result := NewSet[T]()
+	s.elements.Add(elements...)
+}
 
-	// Add elements from the first set that are not in the second set
-	for elem := range s.elements {
-		if !other.Contains(elem) {
-			result.Add(elem)
-		}
-	}
+// Remove deletes an element from the set
+func (s *Set[T]) Remove(element T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.elements.Remove(element)
+}
 
-	return result
+// Contains checks if an element is in the set
+func (s *Set[T]) Contains(element T) (exists bool) {
+	s.read(func(elements PlainSet[T]) {
+		exists = elements.Contains(element)
+	})
+	return exists
+}
+
+// Len returns the number of elements in the set
+func (s *Set[T]) Len() (n int) {
+	s.read(func(elements PlainSet[T]) {
+		n = elements.Len()
+	})
+	return n
+}
+
+// Values returns all elements in the set as a slice
+func (s *Set[T]) Values() (values []T) {
+	s.read(func(elements PlainSet[T]) {
+		values = elements.Values()
+	})
+	return values
+}
+
+// All iterates over a snapshot of the elements in no particular order, so the
+// set may be modified during iteration
+func (s *Set[T]) All() iter.Seq[T] {
+	return slices.Values(s.Values())
 }
 
+// Plain returns a copy of the elements as a PlainSet
+func (s *Set[T]) Plain() (plain PlainSet[T]) {
+	s.read(func(elements PlainSet[T]) {
+		plain = elements.Clone()
+	})
+	return plain
+}
+
+// Clone returns a copy of the set
+func (s *Set[T]) Clone() *Set[T] {
+	return &Set[T]{elements: s.Plain()}
+}
+
+// EquivalentTo checks if both sets have the same elements
+func (s *Set[T]) EquivalentTo(other *Set[T]) bool {
+	return s.compare(other, PlainSet[T].EquivalentTo)
+}
+
+// IsSubset checks if every element of the set is in the other set
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	return s.compare(other, PlainSet[T].IsSubset)
+}
+
+// IsSuperset checks if every element of the other set is in the set
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return s.compare(other, PlainSet[T].IsSuperset)
+}
+
+// Union returns a set of the elements in either set
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	return s.combine(other, PlainSet[T].Union)
+}
+
+// Intersection returns a set of the elements in both sets
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	return s.combine(other, PlainSet[T].Intersection)
+}
+
+// Difference returns a set of the elements in the set but not the other set
 func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	return s.Complement(other)
+	return s.combine(other, PlainSet[T].Difference)
+}
+
+// Complement is an alias of Difference, the relative complement of the other
+// set in the set
+func (s *Set[T]) Complement(other *Set[T]) *Set[T] {
+	return s.Difference(other)
+}
+
+// SymmetricDifference returns a set of the elements in exactly one of the sets
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	return s.combine(other, PlainSet[T].SymmetricDifference)
+}
+
+// SortedValues returns the elements of a set of ordered values in ascending order
+func SortedValues[T cmp.Ordered](s *Set[T]) []T {
+	values := s.Values()
+	slices.Sort(values)
+	return values
 }
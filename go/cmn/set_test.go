@@ -0,0 +1,163 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: set_test
+	Description: Tests for the set types, including concurrent use
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type point struct{ X, Y int }
+
+func TestPlainSetOperations(t *testing.T) {
+	a := NewPlainSet(1, 2, 3)
+	b := NewPlainSet(2, 3, 4)
+
+	tests := map[string]struct {
+		got  PlainSet[int]
+		want []int
+	}{
+		"union":                {a.Union(b), []int{1, 2, 3, 4}},
+		"intersection":         {a.Intersection(b), []int{2, 3}},
+		"difference":           {a.Difference(b), []int{1}},
+		"symmetric difference": {a.SymmetricDifference(b), []int{1, 4}},
+	}
+	for name, test := range tests {
+		got := test.got.Values()
+		slices.Sort(got)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", name, test.want, got)
+		}
+	}
+
+	if !NewPlainSet(2, 3).IsSubset(a) || a.IsSubset(b) || !a.IsSuperset(NewPlainSet(1)) {
+		t.Error("unexpected subset results")
+	}
+	if !a.EquivalentTo(NewPlainSet(3, 2, 1)) || a.EquivalentTo(b) {
+		t.Error("unexpected equivalence results")
+	}
+
+	clone := a.Clone()
+	clone.Add(5)
+	if a.Contains(5) {
+		t.Error("expected the clone to be independent")
+	}
+}
+
+func TestSetStructKeys(t *testing.T) {
+	s := NewSet(point{0, 0}, point{1, 2})
+	s.Add(point{1, 2})
+	if s.Len() != 2 || !s.Contains(point{1, 2}) {
+		t.Errorf("unexpected set contents: %v", s.Values())
+	}
+
+	seen := 0
+	for range s.All() {
+		s.Add(point{seen, 9}) // modifying during iteration must not deadlock
+		seen++
+	}
+	if seen != 2 || s.Len() != 4 {
+		t.Errorf("expected 2 iterations and 4 elements, got %d and %d", seen, s.Len())
+	}
+}
+
+func TestSetSelfOperations(t *testing.T) {
+	s := NewSet(1, 2, 3)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.EquivalentTo(s)
+		s.Union(s)
+		s.Intersection(s)
+		s.Difference(s)
+		s.SymmetricDifference(s)
+		s.IsSubset(s)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("operating on a set with itself deadlocked")
+	}
+	if !slices.Equal(SortedValues(s.Union(NewSet(0))), []int{0, 1, 2, 3}) {
+		t.Error("unexpected union")
+	}
+}
+
+func TestSetConcurrentUse(t *testing.T) {
+	a, b := NewSet[int](), NewSet[int]()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 200 {
+				a.Add(i*1000 + j)
+				b.Add(j)
+				// Opposite operand orders would deadlock if both locks were held
+				a.Union(b)
+				b.Intersection(a)
+				a.EquivalentTo(b)
+				b.IsSuperset(a)
+				a.Clone().Difference(b)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if a.Len() != 1600 || b.Len() != 200 {
+		t.Errorf("expected 1600 and 200 elements, got %d and %d", a.Len(), b.Len())
+	}
+	if got := a.Intersection(b).Len(); got != 200 {
+		t.Errorf("expected an intersection of 200, got %d", got)
+	}
+}
+
+func TestOrderedSet(t *testing.T) {
+	s := NewOrderedSet("c", "a", "b", "a")
+	if !slices.Equal(s.Values(), []string{"c", "a", "b"}) {
+		t.Errorf("unexpected order: %v", s.Values())
+	}
+
+	s.Remove("c")
+	s.Add("c")
+	if !slices.Equal(slices.Collect(s.All()), []string{"a", "b", "c"}) || s.Index("c") != 2 {
+		t.Errorf("unexpected order after re-adding: %v", s.Values())
+	}
+	if s.Index("z") != -1 || s.Contains("z") {
+		t.Error("unexpected missing element results")
+	}
+	if !s.Plain().EquivalentTo(NewPlainSet("a", "b", "c")) {
+		t.Error("unexpected plain set")
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: submit
+	Description: Code for submitting answers and recording them in the local ledger
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LedgerFile is the name of the file in the data directory that records every
+// submission attempt
+const LedgerFile = "submissions.jsonl"
+
+// SubmitOutcome is the result of submitting an answer
+type SubmitOutcome string
+
+const (
+	OutcomeCorrect    SubmitOutcome = "correct"     // OutcomeCorrect means the answer was right
+	OutcomeWrong      SubmitOutcome = "wrong"       // OutcomeWrong means the answer was wrong, with no hint
+	OutcomeTooHigh    SubmitOutcome = "too-high"    // OutcomeTooHigh means the answer was wrong and too high
+	OutcomeTooLow     SubmitOutcome = "too-low"     // OutcomeTooLow means the answer was wrong and too low
+	OutcomeWait       SubmitOutcome = "wait"        // OutcomeWait means an answer was submitted too recently
+	OutcomeWrongLevel SubmitOutcome = "wrong-level" // OutcomeWrongLevel means the part is locked or already solved
+	OutcomeUnknown    SubmitOutcome = "unknown"     // OutcomeUnknown means the response couldn't be parsed
+)
+
+var (
+	articleRe = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRe     = regexp.MustCompile(`<[^>]+>`)
+	waitRe    = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+)
+
+// Submission is a single answer submission attempt
+type Submission struct {
+	Day     int           `json:"day"`
+	Part    int           `json:"part"`
+	Answer  string        `json:"answer"`
+	Outcome SubmitOutcome `json:"outcome"`
+	Wait    time.Duration `json:"wait,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Time    time.Time     `json:"time"`
+}
+
+// IsWrong checks if the submission was rejected as a wrong answer
+func (s *Submission) IsWrong() bool {
+	return s.Outcome == OutcomeWrong || s.Outcome == OutcomeTooHigh || s.Outcome == OutcomeTooLow
+}
+
+// SubmitAnswer posts the answer for the day and part to the site and parses the
+// response
+func (c *Client) SubmitAnswer(day, part int, answer string) (*Submission, error) {
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {answer},
+	}
+	resp, err := c.Do(
+		http.MethodPost,
+		c.DayURL(day)+"/answer",
+		strings.NewReader(form.Encode()),
+		"application/x-www-form-urlencoded",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPStatusError{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	submission := ParseSubmitResponse(string(body))
+	submission.Day = day
+	submission.Part = part
+	submission.Answer = answer
+	submission.Time = time.Now()
+	return submission, nil
+}
+
+// ParseSubmitResponse determines the outcome of a submission from the HTML of
+// the response page
+func ParseSubmitResponse(body string) *Submission {
+	message := body
+	if match := articleRe.FindStringSubmatch(body); match != nil {
+		message = match[1]
+	}
+	message = strings.Join(strings.Fields(html.UnescapeString(tagRe.ReplaceAllString(message, ""))), " ")
+
+	submission := &Submission{Outcome: OutcomeUnknown, Message: message}
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		submission.Outcome = OutcomeCorrect
+	case strings.Contains(message, "That's not the right answer"):
+		submission.Outcome = OutcomeWrong
+		if strings.Contains(message, "too high") {
+			submission.Outcome = OutcomeTooHigh
+		} else if strings.Contains(message, "too low") {
+			submission.Outcome = OutcomeTooLow
+		}
+	case strings.Contains(message, "You gave an answer too recently"):
+		submission.Outcome = OutcomeWait
+		if match := waitRe.FindStringSubmatch(message); match != nil {
+			minutes, _ := strconv.Atoi(match[1])
+			seconds, _ := strconv.Atoi(match[2])
+			submission.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+		}
+	case strings.Contains(message, "You don't seem to be solving the right level"):
+		submission.Outcome = OutcomeWrongLevel
+	}
+	return submission
+}
+
+// Ledger is the local record of every submission attempt
+type Ledger struct {
+	Path        string        // Path is the path of the ledger file
+	Submissions []*Submission // Submissions are the recorded attempts, oldest first
+}
+
+// LoadLedger reads the ledger from the data directory, a missing ledger is
+// treated as empty
+func LoadLedger() (*Ledger, error) {
+	ledger := &Ledger{Path: filepath.Join(DataDir, LedgerFile)}
+
+	file, err := os.Open(ledger.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ledger, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		submission := &Submission{}
+		if err := json.Unmarshal([]byte(line), submission); err != nil {
+			return nil, &InvalidDataError{Line: line, Err: err}
+		}
+		ledger.Submissions = append(ledger.Submissions, submission)
+	}
+	return ledger, scanner.Err()
+}
+
+// Check rejects answers that the ledger shows can't be right: the part was
+// already solved, the same answer was already wrong, or the answer is outside
+// of the bounds from previous too high/too low responses
+func (l *Ledger) Check(day, part int, answer string) error {
+	value, numErr := strconv.Atoi(answer)
+
+	for _, submission := range l.Submissions {
+		if submission.Day != day || submission.Part != part {
+			continue
+		}
+
+		reject := func(reason string) error {
+			return &AnswerRejectedError{Day: day, Part: part, Answer: answer, Reason: reason}
+		}
+
+		if submission.Outcome == OutcomeCorrect {
+			return reject(fmt.Sprintf("already solved with %s", submission.Answer))
+		}
+		if submission.IsWrong() && submission.Answer == answer {
+			return reject("already submitted and was wrong")
+		}
+
+		previous, err := strconv.Atoi(submission.Answer)
+		if numErr != nil || err != nil {
+			continue
+		}
+		if submission.Outcome == OutcomeTooHigh && value >= previous {
+			return reject(fmt.Sprintf("%s was too high", submission.Answer))
+		}
+		if submission.Outcome == OutcomeTooLow && value <= previous {
+			return reject(fmt.Sprintf("%s was too low", submission.Answer))
+		}
+	}
+	return nil
+}
+
+// Record appends the submission to the ledger and its file
+func (l *Ledger) Record(submission *Submission) error {
+	data, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(l.Path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	l.Submissions = append(l.Submissions, submission)
+	return nil
+}
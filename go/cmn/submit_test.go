@@ -0,0 +1,124 @@
+/*
+Copyright 2024 Joseph Bochinski
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the “Software”), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+the Software, and to permit persons to whom the Software is furnished to do so,
+subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+********************************************************************************
+
+	Package: cmn
+	Title: submit_test
+	Description: Tests for submitting answers against a local stand-in
+	Author: Joseph Bochinski
+	Date: 2024-12-16
+
+********************************************************************************
+*/
+package cmn
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSubmitAnswer(t *testing.T) {
+	tests := []struct {
+		message string
+		outcome SubmitOutcome
+		wait    time.Duration
+	}{
+		{"That's the right answer! You are one gold star closer.", OutcomeCorrect, 0},
+		{"That's not the right answer. If you're stuck, make sure you're using the full input data.", OutcomeWrong, 0},
+		{"That's not the right answer; your answer is too high.", OutcomeTooHigh, 0},
+		{"That's not the right answer; your answer is too low.", OutcomeTooLow, 0},
+		{"You gave an answer too recently. You have 1m 5s left to wait.", OutcomeWait, 65 * time.Second},
+		{"You don't seem to be solving the right level. Did you already complete it?", OutcomeWrongLevel, 0},
+		{"Something unexpected", OutcomeUnknown, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.outcome), func(t *testing.T) {
+			requests := 0
+			server := newStandIn(t, test.message, &requests)
+			client := NewClient(WithBaseURL(server.URL), WithSession("token"), WithRateLimit(0))
+
+			submission, err := client.SubmitAnswer(1, 1, "42")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if submission.Outcome != test.outcome || submission.Wait != test.wait {
+				t.Errorf("outcome = %s, wait = %v, want %s, %v", submission.Outcome, submission.Wait, test.outcome, test.wait)
+			}
+			if submission.Day != 1 || submission.Part != 1 || submission.Answer != "42" {
+				t.Errorf("submission = %+v", submission)
+			}
+		})
+	}
+}
+
+func TestLedgerCheck(t *testing.T) {
+	useTempDataDir(t)
+	ledger, err := LoadLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, submission := range []*Submission{
+		{Day: 1, Part: 1, Answer: "100", Outcome: OutcomeTooHigh},
+		{Day: 1, Part: 1, Answer: "10", Outcome: OutcomeTooLow},
+		{Day: 1, Part: 1, Answer: "50", Outcome: OutcomeWrong},
+		{Day: 1, Part: 2, Answer: "7", Outcome: OutcomeCorrect},
+	} {
+		if err := ledger.Record(submission); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The ledger should round trip through its file
+	if ledger, err = LoadLedger(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Submissions) != 4 {
+		t.Fatalf("loaded %d submissions, want 4", len(ledger.Submissions))
+	}
+	if ledger.Path != filepath.Join(DataDir, LedgerFile) {
+		t.Errorf("ledger path = %s", ledger.Path)
+	}
+
+	tests := []struct {
+		part     int
+		answer   string
+		rejected bool
+	}{
+		{1, "100", true},
+		{1, "150", true},
+		{1, "10", true},
+		{1, "5", true},
+		{1, "50", true},
+		{1, "51", false},
+		{1, "abc", false},
+		{2, "8", true},
+		{1, "99", false},
+	}
+	for _, test := range tests {
+		err := ledger.Check(1, test.part, test.answer)
+		if rejected := err != nil; rejected != test.rejected {
+			t.Errorf("Check(part %d, %s) rejected = %v, want %v", test.part, test.answer, rejected, test.rejected)
+		}
+	}
+}
@@ -34,16 +34,21 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 const (
-	DataDir = "/home/joseph/coding_base/advent2024/go/data"
+	DataDirEnvVar     = "ADVENT_DATA_DIR"     // DataDirEnvVar is the env variable that overrides the default DataDir
+	RequireDataEnvVar = "ADVENT_REQUIRE_DATA" // RequireDataEnvVar makes missing puzzle data fail tests instead of skipping them
+	defaultDataDir    = "/home/joseph/coding_base/advent2024/go/data"
 )
 
-var ActiveCmd *cobra.Command
+// DataDir is the directory containing the puzzle data for each day
+var DataDir = GetEnvD(DataDirEnvVar, defaultDataDir)
 
 // AbsDistInt Returns the absolute value of the difference between two ints
 func AbsDistInt(a, b int) int {
@@ -54,11 +59,18 @@ func AbsDistInt(a, b int) int {
 	return dist
 }
 
-// InitDailyCmd is a convenience function to add the day flag to a command and set
-// the ActiveCmd variable
-func InitDailyCmd(cmd *cobra.Command, day int) {
-	ActiveCmd = cmd
-	ActiveCmd.Flags().IntP("day-num", "d", day, "Day of the Advent of Code challenge")
+// InitDailyCmd is a convenience function to add the day and expect flags to a
+// command and register the day's solver functions
+func InitDailyCmd(cmd *cobra.Command, day int, solvers ...HandlerFunc) {
+	cmd.Flags().IntP("day-num", "d", day, "Day of the Advent of Code challenge, must match the command's day")
+	cmd.Flags().StringP("expect", "e", "", "The expected answer, defaults to the one in the day's "+AnswersFile+" file")
+	cmd.SilenceUsage = true
+	RegisterDay(day, cmd, solvers...)
+}
+
+// DayDir returns the data directory for the given day
+func DayDir(day int) string {
+	return filepath.Join(DataDir, "day"+strconv.Itoa(day))
 }
 
 // DistInt calculates the distance between two ints and returns both the actual
@@ -85,12 +97,13 @@ func GetInput(prompt string) (input string, err error) {
 	return strings.TrimSpace(input), nil
 }
 
-// HandleErr is a convenience function for simple top level error handling
-func HandleErr(err error) {
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+// GetEnvD retrieves the value of the env variable, defaults to the provided value
+// if it's unset or empty
+func GetEnvD(name, defaultVal string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
 	}
+	return defaultVal
 }
 
 // RemFromSlice removes an element from a slice at a given index